@@ -7,15 +7,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"net/netip"
+	"reflect"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 
 	"go.githedgehog.com/gateway/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -25,8 +30,10 @@ var ErrInvalidGW = errors.New("invalid gateway")
 
 // GatewaySpec defines the desired state of Gateway.
 type GatewaySpec struct {
-	// ProtocolIP is used as a loopback IP and BGP Router ID
+	// ProtocolIP is used as a loopback IP and BGP Router ID, must be an IPv4 /32 prefix
 	ProtocolIP string `json:"protocolIP,omitempty"`
+	// ProtocolIPv6 is an optional IPv6 loopback IP (/128 prefix) used as a source for IPv6 BGP sessions
+	ProtocolIPv6 string `json:"protocolIPv6,omitempty"`
 	// VTEP IP to be used by the gateway
 	VTEPIP string `json:"vtepIP,omitempty"`
 	// VTEP MAC address to be used by the gateway
@@ -45,8 +52,96 @@ type GatewaySpec struct {
 	Profiling GatewayProfiling `json:"profiling,omitempty"`
 	// Workers defines the number of worker threads to use for dataplane
 	Workers uint8 `json:"workers,omitempty"`
+	// Resources defines the CPUs, memory and hugepages reserved for the dataplane
+	Resources GatewayDataplaneResources `json:"resources,omitempty"`
 	// Groups is a list of group memberships for the gateway
 	Groups []GatewayGroupMembership `json:"groups,omitempty"`
+	// NodeName is the name of the node to run the gateway on, if neither NodeName nor NodeSelector is set the node
+	// with the hostname equal to the gateway name is used
+	NodeName string `json:"nodeName,omitempty"`
+	// NodeSelector selects the node to run the gateway on by labels, it must not match more than one node and it's
+	// mutually exclusive with NodeName
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Images overrides the images from the gateway group and controller config for this gateway only, e.g. to canary
+	// a new dataplane version
+	Images GatewayImages `json:"images,omitempty"`
+	// Drain takes the gateway out of service by de-preferring its advertisements, so traffic shifts to the other
+	// members of its gateway groups
+	Drain GatewayDrain `json:"drain,omitempty"`
+}
+
+// GatewayDrain defines the configuration for draining the gateway before maintenance
+type GatewayDrain struct {
+	// Enabled turns on draining of the gateway
+	Enabled bool `json:"enabled,omitempty"`
+	// Mode is how the advertisements are de-preferred: prepend (AS-path prepend) or community (drain community from
+	// the controller config, graceful shutdown by default)
+	Mode GatewayDrainMode `json:"mode,omitempty"`
+	// Prepend is the number of times the gateway ASN is prepended to the AS-path in prepend mode
+	Prepend uint8 `json:"prepend,omitempty"`
+	// ThresholdPPS is the total peering traffic rate in packets per second below which the drain is complete
+	ThresholdPPS uint64 `json:"thresholdPPS,omitempty"`
+}
+
+type GatewayDrainMode string
+
+const (
+	GatewayDrainModePrepend   GatewayDrainMode = "prepend"
+	GatewayDrainModeCommunity GatewayDrainMode = "community"
+)
+
+var GatewayDrainModes = []GatewayDrainMode{
+	GatewayDrainModePrepend,
+	GatewayDrainModeCommunity,
+}
+
+const (
+	DefaultDrainPrepend      = 3
+	MaxDrainPrepend          = 10
+	DefaultDrainThresholdPPS = 100
+)
+
+// GatewayImages defines the images for the gateway components, empty fields fall back to the less specific level
+// (gateway, gateway group, controller config)
+type GatewayImages struct {
+	// Dataplane is the dataplane image reference
+	Dataplane string `json:"dataplane,omitempty"`
+	// FRR is the FRR image reference
+	FRR string `json:"frr,omitempty"`
+	// Toolbox is the toolbox image reference used for init containers
+	Toolbox string `json:"toolbox,omitempty"`
+}
+
+// IsEmpty returns true if none of the images are set
+func (img GatewayImages) IsEmpty() bool {
+	return img == GatewayImages{}
+}
+
+// Override returns images with the non-empty fields of other taking precedence
+func (img GatewayImages) Override(other GatewayImages) GatewayImages {
+	if other.Dataplane != "" {
+		img.Dataplane = other.Dataplane
+	}
+	if other.FRR != "" {
+		img.FRR = other.FRR
+	}
+	if other.Toolbox != "" {
+		img.Toolbox = other.Toolbox
+	}
+
+	return img
+}
+
+var imageRefRegex = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(:[0-9]+)?(/[a-z0-9]+([._-][a-z0-9]+)*)*(:[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?(@sha256:[a-f0-9]{64})?$`)
+
+func (img GatewayImages) validate() error {
+	for name, ref := range map[string]string{"dataplane": img.Dataplane, "frr": img.FRR, "toolbox": img.Toolbox} {
+		if ref != "" && !imageRefRegex.MatchString(ref) {
+			return fmt.Errorf("invalid %s image reference %q", name, ref) //nolint:err113
+		}
+	}
+
+	return nil
 }
 
 // GatewayInterface defines the configuration for a gateway interface
@@ -55,22 +150,210 @@ type GatewayInterface struct {
 	PCI string `json:"pci,omitempty"`
 	// Kernel is the kernel name of the interface to use (required for kernel driver), e.g. enp2s1
 	Kernel string `json:"kernel,omitempty"`
-	// IPs is the list of IP address to assign to the interface
+	// IPs is the list of IP address to assign to the interface, both IPv4 and IPv6 are supported
 	IPs []string `json:"ips,omitempty"`
 	// MTU for the interface
 	MTU uint32 `json:"mtu,omitempty"`
+	// Bond makes the interface a link aggregation (LACP) of the member interfaces, PCI and kernel must not be set
+	Bond *GatewayInterfaceBond `json:"bond,omitempty"`
+	// VLAN makes the interface an 802.1Q sub-interface of the parent interface, PCI and kernel must not be set
+	VLAN *GatewayInterfaceVLAN `json:"vlan,omitempty"`
+}
+
+// GatewayInterfaceVLAN defines the configuration for an 802.1Q VLAN sub-interface
+type GatewayInterfaceVLAN struct {
+	// Parent is the name of the gateway interface the sub-interface is created on
+	Parent string `json:"parent,omitempty"`
+	// ID is the VLAN ID (1-4094), unique per parent interface
+	ID uint16 `json:"id,omitempty"`
+}
+
+// GatewayInterfaceBond defines the configuration for a link aggregation (bond) interface using LACP
+type GatewayInterfaceBond struct {
+	// Members is the list of interfaces (names from the gateway interfaces) aggregated by the bond, members inherit
+	// the bond MTU and can't have IPs or be used standalone
+	Members []string `json:"members,omitempty"`
+	// LACPMode is the LACP mode, active (default) or passive
+	LACPMode GatewayBondLACPMode `json:"lacpMode,omitempty"`
+	// LACPRate is the rate of LACPDUs requested from the partner, slow (default, every 30s) or fast (every 1s)
+	LACPRate GatewayBondLACPRate `json:"lacpRate,omitempty"`
+	// HashPolicy is the transmit hash policy used to select a member link, layer3+4 by default
+	HashPolicy GatewayBondHashPolicy `json:"hashPolicy,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=active;passive
+type GatewayBondLACPMode string
+
+const (
+	GatewayBondLACPModeActive  GatewayBondLACPMode = "active"
+	GatewayBondLACPModePassive GatewayBondLACPMode = "passive"
+)
+
+var GatewayBondLACPModes = []GatewayBondLACPMode{
+	GatewayBondLACPModeActive,
+	GatewayBondLACPModePassive,
+}
+
+// +kubebuilder:validation:Enum=slow;fast
+type GatewayBondLACPRate string
+
+const (
+	GatewayBondLACPRateSlow GatewayBondLACPRate = "slow"
+	GatewayBondLACPRateFast GatewayBondLACPRate = "fast"
+)
+
+var GatewayBondLACPRates = []GatewayBondLACPRate{
+	GatewayBondLACPRateSlow,
+	GatewayBondLACPRateFast,
+}
+
+// +kubebuilder:validation:Enum=layer2;layer2+3;layer3+4;encap2+3;encap3+4
+type GatewayBondHashPolicy string
+
+const (
+	GatewayBondHashPolicyLayer2  GatewayBondHashPolicy = "layer2"
+	GatewayBondHashPolicyLayer23 GatewayBondHashPolicy = "layer2+3"
+	GatewayBondHashPolicyLayer34 GatewayBondHashPolicy = "layer3+4"
+	GatewayBondHashPolicyEncap23 GatewayBondHashPolicy = "encap2+3"
+	GatewayBondHashPolicyEncap34 GatewayBondHashPolicy = "encap3+4"
+)
+
+var GatewayBondHashPolicies = []GatewayBondHashPolicy{
+	GatewayBondHashPolicyLayer2,
+	GatewayBondHashPolicyLayer23,
+	GatewayBondHashPolicyLayer34,
+	GatewayBondHashPolicyEncap23,
+	GatewayBondHashPolicyEncap34,
+}
+
+// BondMembers returns a map of interface names aggregated by bonds to the name of the bond
+func (spec *GatewaySpec) BondMembers() map[string]string {
+	members := map[string]string{}
+	for name, iface := range spec.Interfaces {
+		if iface.Bond == nil {
+			continue
+		}
+
+		for _, member := range iface.Bond.Members {
+			members[member] = name
+		}
+	}
+
+	return members
 }
 
 // GatewayBGPNeighbor defines the configuration for a BGP neighbor
 type GatewayBGPNeighbor struct {
 	// Source is the source interface for the BGP neighbor configuration
 	Source string `json:"source,omitempty"`
-	// IP is the IP address of the BGP neighbor
+	// IP is the IP address of the BGP neighbor, IPv4 or IPv6
 	IP string `json:"ip,omitempty"`
 	// ASN is the remote ASN of the BGP neighbor
 	ASN uint32 `json:"asn,omitempty"`
+	// Auth is the optional authentication configuration for the BGP session
+	Auth *GatewayBGPNeighborAuth `json:"auth,omitempty"`
+	// Timers are the optional BGP timers for the session, FRR defaults are used if not set
+	Timers *GatewayBGPNeighborTimers `json:"timers,omitempty"`
+	// BFD is the optional BFD configuration for the neighbor, overrides the controller-wide fabric BFD setting
+	BFD *GatewayBGPNeighborBFD `json:"bfd,omitempty"`
+	// EBGPMultihop is the max number of hops to the eBGP neighbor, 0 means directly connected
+	EBGPMultihop uint8 `json:"ebgpMultihop,omitempty"`
+	// AddressFamilies is the list of address families to enable for the neighbor, defaults to unicast for the
+	// neighbor IP family and L2VPN EVPN if empty
+	AddressFamilies []GatewayBGPAddressFamily `json:"addressFamilies,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=tcp-md5;tcp-ao
+type GatewayBGPAuthType string
+
+const (
+	GatewayBGPAuthTypeTCPMD5 GatewayBGPAuthType = "tcp-md5"
+	GatewayBGPAuthTypeTCPAO  GatewayBGPAuthType = "tcp-ao"
+)
+
+var GatewayBGPAuthTypes = []GatewayBGPAuthType{
+	GatewayBGPAuthTypeTCPMD5,
+	GatewayBGPAuthTypeTCPAO,
+}
+
+// +kubebuilder:validation:Enum=hmac-sha-1-96;aes-128-cmac-96
+type GatewayBGPAuthAlgorithm string
+
+const (
+	GatewayBGPAuthAlgorithmHMACSHA196   GatewayBGPAuthAlgorithm = "hmac-sha-1-96"
+	GatewayBGPAuthAlgorithmAES128CMAC96 GatewayBGPAuthAlgorithm = "aes-128-cmac-96"
+)
+
+var GatewayBGPAuthAlgorithms = []GatewayBGPAuthAlgorithm{
+	GatewayBGPAuthAlgorithmHMACSHA196,
+	GatewayBGPAuthAlgorithmAES128CMAC96,
+}
+
+// GatewayBGPNeighborAuth defines the authentication for a BGP session, the password itself is never stored in the
+// gateway spec and is delivered to the FRR pod from the referenced secret
+type GatewayBGPNeighborAuth struct {
+	// Type is the authentication type, tcp-md5 or tcp-ao
+	Type GatewayBGPAuthType `json:"type,omitempty"`
+	// SecretRef is the reference to the key in a secret in the gateway namespace that holds the password
+	SecretRef GatewaySecretKeyRef `json:"secretRef,omitempty"`
+	// Algorithm is the TCP-AO MAC algorithm, only used with tcp-ao
+	Algorithm GatewayBGPAuthAlgorithm `json:"algorithm,omitempty"`
+	// SendID is the TCP-AO send key ID, only used with tcp-ao
+	SendID uint8 `json:"sendID,omitempty"`
+	// RecvID is the TCP-AO receive key ID, only used with tcp-ao
+	RecvID uint8 `json:"recvID,omitempty"`
+}
+
+// GatewaySecretKeyRef is a reference to a key in a secret
+type GatewaySecretKeyRef struct {
+	// Name is the name of the secret
+	Name string `json:"name,omitempty"`
+	// Key is the key in the secret data
+	Key string `json:"key,omitempty"`
+}
+
+// GatewayBGPNeighborTimers defines the BGP timers for a neighbor
+type GatewayBGPNeighborTimers struct {
+	// Keepalive is the keepalive interval in seconds
+	Keepalive uint16 `json:"keepalive,omitempty"`
+	// Hold is the hold time in seconds, 0 disables keepalives, otherwise at least 3
+	Hold uint16 `json:"hold,omitempty"`
+	// ConnectRetry is the connect retry interval in seconds
+	ConnectRetry uint16 `json:"connectRetry,omitempty"`
+}
+
+// GatewayBGPNeighborBFD defines the BFD profile for a neighbor
+type GatewayBGPNeighborBFD struct {
+	// Enabled defines if BFD is enabled for the neighbor
+	Enabled bool `json:"enabled,omitempty"`
+	// DetectMultiplier is the number of missed packets before the session is considered down
+	DetectMultiplier uint8 `json:"detectMultiplier,omitempty"`
+	// ReceiveInterval is the minimum receive interval in milliseconds
+	ReceiveInterval uint32 `json:"receiveInterval,omitempty"`
+	// TransmitInterval is the minimum transmit interval in milliseconds
+	TransmitInterval uint32 `json:"transmitInterval,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=ipv4-unicast;ipv6-unicast;l2vpn-evpn
+type GatewayBGPAddressFamily string
+
+const (
+	GatewayBGPAddressFamilyIPv4Unicast GatewayBGPAddressFamily = "ipv4-unicast"
+	GatewayBGPAddressFamilyIPv6Unicast GatewayBGPAddressFamily = "ipv6-unicast"
+	GatewayBGPAddressFamilyL2VPNEVPN   GatewayBGPAddressFamily = "l2vpn-evpn"
+)
+
+var GatewayBGPAddressFamilies = []GatewayBGPAddressFamily{
+	GatewayBGPAddressFamilyIPv4Unicast,
+	GatewayBGPAddressFamilyIPv6Unicast,
+	GatewayBGPAddressFamilyL2VPNEVPN,
+}
+
+const (
+	DefaultBGPBFDDetectMultiplier = 3
+	DefaultBGPBFDInterval         = 300
+)
+
 // GatewayLogs defines the configuration for logging levels
 type GatewayLogs struct {
 	Default GatewayLogLevel            `json:"default,omitempty"`
@@ -97,26 +380,166 @@ var GatewayLogLevels = []GatewayLogLevel{
 	GatewayLogLevelTrace,
 }
 
+// GatewayProfiling defines the configuration for continuous profiling of the dataplane
 type GatewayProfiling struct {
+	// Enabled turns on profiling
 	Enabled bool `json:"enabled,omitempty"`
+	// URL is the Pyroscope compatible endpoint to push profiles to, e.g. http://alloy.fab.svc.cluster.local:4040,
+	// defaults to the one from the controller config
+	URL string `json:"url,omitempty"`
+	// SampleRate is the sampling frequency in Hz
+	SampleRate uint32 `json:"sampleRate,omitempty"`
+	// Tags are attached to all profiles in addition to the gateway name
+	Tags map[string]string `json:"tags,omitempty"`
+	// ProfileTypes is the list of profile types to collect: cpu, alloc
+	ProfileTypes []GatewayProfileType `json:"profileTypes,omitempty"`
+}
+
+type GatewayProfileType string
+
+const (
+	GatewayProfileTypeCPU   GatewayProfileType = "cpu"
+	GatewayProfileTypeAlloc GatewayProfileType = "alloc"
+)
+
+var GatewayProfileTypes = []GatewayProfileType{
+	GatewayProfileTypeCPU,
+	GatewayProfileTypeAlloc,
+}
+
+const (
+	DefaultProfilingSampleRate = 100
+	MaxProfilingSampleRate     = 1000
+)
+
+// GatewayDataplaneResources defines the resources reserved for the dataplane container, setting CPUs results in the
+// Guaranteed QoS class for the dataplane pod which together with the static CPU manager policy on the node gives the
+// dataplane exclusive CPUs
+type GatewayDataplaneResources struct {
+	// CPUs is the number of CPUs requested (and limited to) for the dataplane container
+	CPUs uint16 `json:"cpus,omitempty"`
+	// CPUSet is the list of CPUs to pin dataplane workers to in the Linux cpuset format, e.g. 2-5,10, they should be
+	// isolated on the node and located on the same NUMA node as the NICs
+	CPUSet string `json:"cpuSet,omitempty"`
+	// NUMANode is the NUMA node of the NICs to allocate dataplane memory from, 0-63
+	// +kubebuilder:validation:Maximum=63
+	NUMANode *uint8 `json:"numaNode,omitempty"`
+	// Memory is the amount of regular (non-hugepage) memory for the dataplane container, e.g. 2Gi
+	Memory string `json:"memory,omitempty"`
+	// HugepageSize is the size of the hugepages to reserve, 2Mi or 1Gi
+	HugepageSize GatewayHugepageSize `json:"hugepageSize,omitempty"`
+	// Hugepages is the number of hugepages of HugepageSize to reserve for the dataplane
+	Hugepages uint32 `json:"hugepages,omitempty"`
+}
+
+type GatewayHugepageSize string
+
+const (
+	GatewayHugepageSize2Mi GatewayHugepageSize = "2Mi"
+	GatewayHugepageSize1Gi GatewayHugepageSize = "1Gi"
+)
+
+var GatewayHugepageSizes = []GatewayHugepageSize{
+	GatewayHugepageSize2Mi,
+	GatewayHugepageSize1Gi,
 }
 
+const (
+	DefaultDataplaneMemory = "1Gi"
+	// MaxCPUSetCPU is the max CPU number accepted in the CPUSet
+	MaxCPUSetCPU = 4095
+)
+
 type GatewayGroupMembership struct {
 	// Name is the name of the group to which the gateway belongs
 	Name string `json:"name,omitempty"`
-	// Priority is the priority of the gateway within the group
+	// Priority is the priority of the gateway within the group, it has to be unique in active-standby groups unless
+	// it's 0 (default) in which case the members with the same priority are ordered by gateway name
 	Priority uint32 `json:"priority,omitempty"`
+	// Weight is the share of the group traffic for the gateway relative to other members, it's only supported in
+	// ecmp groups and defaults to 1
+	Weight uint8 `json:"weight,omitempty"`
 }
 
+const (
+	// GatewayConditionReady is true when the agent is alive, the latest config is applied and BGP sessions are up
+	GatewayConditionReady = "Ready"
+	// GatewayConditionConfigApplied is true when the agent applied the latest generation of the config
+	GatewayConditionConfigApplied = "ConfigApplied"
+	// GatewayConditionAgentAlive is true when the agent heartbeat is fresh
+	GatewayConditionAgentAlive = "AgentAlive"
+	// GatewayConditionBGPEstablished is true when all BGP sessions reported by the agent are established
+	GatewayConditionBGPEstablished = "BGPEstablished"
+	// GatewayConditionDrained is only set while the gateway is draining and it's true when the drain config is applied
+	// and the traffic is below the drain threshold
+	GatewayConditionDrained = "Drained"
+)
+
+const (
+	GatewayReasonReady          = "Ready"
+	GatewayReasonNotReady       = "NotReady"
+	GatewayReasonApplied        = "Applied"
+	GatewayReasonApplyPending   = "ApplyPending"
+	GatewayReasonAgentAlive     = "HeartbeatFresh"
+	GatewayReasonNoHeartbeat    = "NoHeartbeat"
+	GatewayReasonStaleHeartbeat = "HeartbeatStale"
+	GatewayReasonEstablished    = "Established"
+	GatewayReasonNotEstablished = "NotEstablished"
+	GatewayReasonNoBGPState     = "NoBGPState"
+	GatewayReasonDrained        = "Drained"
+	GatewayReasonDraining       = "Draining"
+)
+
 // GatewayStatus defines the observed state of Gateway.
-type GatewayStatus struct{}
+type GatewayStatus struct {
+	// Conditions describe the current state of the gateway derived from its agent
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []kmetav1.Condition `json:"conditions,omitempty"`
+	// LastHeartbeat is the time of the last heartbeat from the gateway agent seen by the controller, it is only
+	// refreshed on reconcile so it may lag behind the agent by up to the heartbeat timeout
+	LastHeartbeat kmetav1.Time `json:"lastHeartbeat,omitempty"`
+	// LastAppliedTime is the time of the last successful configuration application by the agent
+	LastAppliedTime kmetav1.Time `json:"lastAppliedTime,omitempty"`
+	// LastAppliedGen is the generation of the agent config that was last applied
+	LastAppliedGen int64 `json:"lastAppliedGen,omitempty"`
+	// DesiredGen is the current generation of the agent config
+	DesiredGen int64 `json:"desiredGen,omitempty"`
+	// AgentVersion is the version of the gateway agent
+	AgentVersion string `json:"agentVersion,omitempty"`
+	// DataplaneVersion is the version of the dataplane reported by the agent
+	DataplaneVersion string `json:"dataplaneVersion,omitempty"`
+	// BGP is the summary of the BGP sessions reported by the agent
+	BGP GatewayBGPSummary `json:"bgp,omitempty"`
+	// Images are the images the gateway is running, updated once the rollout of the gateway pods is completed
+	Images GatewayImages `json:"images,omitempty"`
+}
+
+// GatewayBGPSummary is a summary of the BGP sessions across all VRFs
+type GatewayBGPSummary struct {
+	// Neighbors is the number of enabled BGP neighbors
+	Neighbors uint32 `json:"neighbors,omitempty"`
+	// Established is the number of BGP neighbors in the established state
+	Established uint32 `json:"established,omitempty"`
+}
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=gw
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,priority=0
+// +kubebuilder:printcolumn:name="Drained",type=string,JSONPath=`.status.conditions[?(@.type=="Drained")].status`,priority=0
 // +kubebuilder:printcolumn:name="ProtoIP",type=string,JSONPath=`.spec.protocolIP`,priority=0
 // +kubebuilder:printcolumn:name="VTEPIP",type=string,JSONPath=`.spec.vtepIP`,priority=0
 // +kubebuilder:printcolumn:name="Groups",type=string,JSONPath=`.spec.groups`,priority=1
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`,priority=1
+// +kubebuilder:printcolumn:name="Applied",type=date,JSONPath=`.status.lastAppliedTime`,priority=1
+// +kubebuilder:printcolumn:name="AppliedG",type=integer,JSONPath=`.status.lastAppliedGen`,priority=1
+// +kubebuilder:printcolumn:name="DesiredG",type=integer,JSONPath=`.status.desiredGen`,priority=1
+// +kubebuilder:printcolumn:name="Heartbeat",type=date,JSONPath=`.status.lastHeartbeat`,priority=1
+// +kubebuilder:printcolumn:name="BGP",type=integer,JSONPath=`.status.bgp.established`,priority=1
+// +kubebuilder:printcolumn:name="Dataplane",type=string,JSONPath=`.status.dataplaneVersion`,priority=1
+// +kubebuilder:printcolumn:name="DPImage",type=string,JSONPath=`.status.images.dataplane`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
 // Gateway is the Schema for the gateways API.
 type Gateway struct {
@@ -140,6 +563,37 @@ func init() {
 	SchemeBuilder.Register(&Gateway{}, &GatewayList{})
 }
 
+// Node returns the name of the node the gateway is placed on if it's not selected by labels, the gateway name is
+// used as a node name for backward compatibility if NodeName isn't set
+func (gw *Gateway) Node() string {
+	if gw.Spec.NodeName != "" {
+		return gw.Spec.NodeName
+	}
+
+	return gw.Name
+}
+
+// nodes returns the names of the nodes the gateway is going to run on, it's the nodes matching the selector (if set)
+// or the single node from Node() otherwise
+func (gw *Gateway) nodes(ctx context.Context, kube kclient.Reader) ([]string, error) {
+	if len(gw.Spec.NodeSelector) == 0 {
+		return []string{gw.Node()}, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := kube.List(ctx, nodes, kclient.MatchingLabels(gw.Spec.NodeSelector)); err != nil {
+		return nil, fmt.Errorf("listing nodes for gateway %s: %w", gw.Name, err)
+	}
+
+	names := []string{}
+	for _, node := range nodes.Items {
+		names = append(names, node.Name)
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
 func (gw *Gateway) Default() {
 	if gw.Namespace == "" {
 		gw.Namespace = kmetav1.NamespaceDefault
@@ -157,6 +611,109 @@ func (gw *Gateway) Default() {
 		gw.Spec.Workers = 4
 	}
 
+	if gw.Spec.Profiling.Enabled {
+		if gw.Spec.Profiling.SampleRate == 0 {
+			gw.Spec.Profiling.SampleRate = DefaultProfilingSampleRate
+		}
+		if len(gw.Spec.Profiling.ProfileTypes) == 0 {
+			gw.Spec.Profiling.ProfileTypes = []GatewayProfileType{GatewayProfileTypeCPU}
+		}
+		slices.Sort(gw.Spec.Profiling.ProfileTypes)
+	}
+
+	if gw.Spec.Drain.Enabled {
+		if gw.Spec.Drain.Mode == "" {
+			gw.Spec.Drain.Mode = GatewayDrainModePrepend
+		}
+		if gw.Spec.Drain.Mode == GatewayDrainModePrepend && gw.Spec.Drain.Prepend == 0 {
+			gw.Spec.Drain.Prepend = DefaultDrainPrepend
+		}
+		if gw.Spec.Drain.ThresholdPPS == 0 {
+			gw.Spec.Drain.ThresholdPPS = DefaultDrainThresholdPPS
+		}
+	}
+
+	res := &gw.Spec.Resources
+	if res.Hugepages > 0 && res.HugepageSize == "" {
+		res.HugepageSize = GatewayHugepageSize2Mi
+	}
+	if (res.CPUs > 0 || res.Hugepages > 0) && res.Memory == "" {
+		res.Memory = DefaultDataplaneMemory
+	}
+	if mem, err := resource.ParseQuantity(res.Memory); err == nil {
+		res.Memory = mem.String()
+	}
+
+	for name, iface := range gw.Spec.Interfaces {
+		if iface.Bond == nil {
+			continue
+		}
+
+		if iface.Bond.LACPMode == "" {
+			iface.Bond.LACPMode = GatewayBondLACPModeActive
+		}
+		if iface.Bond.LACPRate == "" {
+			iface.Bond.LACPRate = GatewayBondLACPRateSlow
+		}
+		if iface.Bond.HashPolicy == "" {
+			iface.Bond.HashPolicy = GatewayBondHashPolicyLayer34
+		}
+		slices.Sort(iface.Bond.Members)
+
+		// members inherit the bond MTU
+		for _, memberName := range iface.Bond.Members {
+			member, exists := gw.Spec.Interfaces[memberName]
+			if !exists || member.MTU != 0 {
+				continue
+			}
+
+			member.MTU = iface.MTU
+			gw.Spec.Interfaces[memberName] = member
+		}
+		gw.Spec.Interfaces[name] = iface
+	}
+
+	// sub-interfaces inherit the parent MTU
+	for name, iface := range gw.Spec.Interfaces {
+		if iface.VLAN == nil || iface.MTU != 0 {
+			continue
+		}
+
+		if parent, exists := gw.Spec.Interfaces[iface.VLAN.Parent]; exists {
+			iface.MTU = parent.MTU
+			gw.Spec.Interfaces[name] = iface
+		}
+	}
+
+	gw.Spec.ProtocolIP = canonicalPrefix(gw.Spec.ProtocolIP)
+	gw.Spec.ProtocolIPv6 = canonicalPrefix(gw.Spec.ProtocolIPv6)
+	gw.Spec.VTEPIP = canonicalPrefix(gw.Spec.VTEPIP)
+	for _, iface := range gw.Spec.Interfaces {
+		for idx, ip := range iface.IPs {
+			iface.IPs[idx] = canonicalPrefix(ip)
+		}
+	}
+	for idx := range gw.Spec.Neighbors {
+		neigh := &gw.Spec.Neighbors[idx]
+		neigh.IP = canonicalAddr(neigh.IP)
+
+		if neigh.Auth != nil && neigh.Auth.Type == GatewayBGPAuthTypeTCPAO && neigh.Auth.Algorithm == "" {
+			neigh.Auth.Algorithm = GatewayBGPAuthAlgorithmHMACSHA196
+		}
+
+		if neigh.BFD != nil && neigh.BFD.Enabled {
+			if neigh.BFD.DetectMultiplier == 0 {
+				neigh.BFD.DetectMultiplier = DefaultBGPBFDDetectMultiplier
+			}
+			if neigh.BFD.ReceiveInterval == 0 {
+				neigh.BFD.ReceiveInterval = DefaultBGPBFDInterval
+			}
+			if neigh.BFD.TransmitInterval == 0 {
+				neigh.BFD.TransmitInterval = DefaultBGPBFDInterval
+			}
+		}
+	}
+
 	slices.SortFunc(gw.Spec.Groups, func(a, b GatewayGroupMembership) int {
 		return strings.Compare(a.Name, b.Name)
 	})
@@ -170,6 +727,25 @@ func (gw *Gateway) Default() {
 
 var linuxIfaceNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.-]{0,8}[a-zA-Z0-9]$`)
 
+// canonicalPrefix returns the canonical text form of the prefix (e.g. lowercase and compressed for IPv6) or the
+// original string if it can't be parsed so validation can report it
+func canonicalPrefix(in string) string {
+	if prefix, err := netip.ParsePrefix(in); err == nil {
+		return prefix.String()
+	}
+
+	return in
+}
+
+// canonicalAddr returns the canonical text form of the address or the original string if it can't be parsed
+func canonicalAddr(in string) string {
+	if addr, err := netip.ParseAddr(in); err == nil {
+		return addr.String()
+	}
+
+	return in
+}
+
 func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *meta.GatewayCtrlConfig) error {
 	if gw.Namespace != kmetav1.NamespaceDefault {
 		return fmt.Errorf("gateway namespace must be %s: %w", kmetav1.NamespaceDefault, ErrInvalidGW)
@@ -179,27 +755,87 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 		return fmt.Errorf("workers should be between 1 and 64: %w", ErrInvalidGW)
 	}
 
+	if err := gw.Spec.Resources.validate(gw.Spec.Workers); err != nil {
+		return err
+	}
+
+	if err := gw.Spec.Profiling.validate(); err != nil {
+		return err
+	}
+
+	if err := gw.Spec.Images.validate(); err != nil {
+		return errors.Join(err, ErrInvalidGW)
+	}
+
+	if drain := gw.Spec.Drain; drain.Enabled {
+		if !slices.Contains(GatewayDrainModes, drain.Mode) {
+			return fmt.Errorf("invalid drain mode %q, must be one of %v: %w", drain.Mode, GatewayDrainModes, ErrInvalidGW)
+		}
+		if drain.Mode == GatewayDrainModePrepend && (drain.Prepend == 0 || drain.Prepend > MaxDrainPrepend) {
+			return fmt.Errorf("drain prepend should be between 1 and %d: %w", MaxDrainPrepend, ErrInvalidGW)
+		}
+		if drain.Mode == GatewayDrainModeCommunity && drain.Prepend != 0 {
+			return fmt.Errorf("drain prepend can only be set in prepend mode: %w", ErrInvalidGW)
+		}
+	}
+
+	if gw.Spec.NodeName != "" && len(gw.Spec.NodeSelector) > 0 {
+		return fmt.Errorf("only one of nodeName or nodeSelector can be set: %w", ErrInvalidGW)
+	}
+	if gw.Spec.NodeName != "" {
+		if errs := validation.IsDNS1123Subdomain(gw.Spec.NodeName); len(errs) > 0 {
+			return fmt.Errorf("invalid node name %q: %s: %w", gw.Spec.NodeName, strings.Join(errs, ", "), ErrInvalidGW)
+		}
+	}
+	for key, value := range gw.Spec.NodeSelector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid node selector key %q: %s: %w", key, strings.Join(errs, ", "), ErrInvalidGW)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid node selector value %q for key %q: %s: %w", value, key, strings.Join(errs, ", "), ErrInvalidGW)
+		}
+	}
+
 	protoIP, err := netip.ParsePrefix(gw.Spec.ProtocolIP)
 	if err != nil {
 		return fmt.Errorf("invalid ProtocolIP %s: %w", gw.Spec.ProtocolIP, errors.Join(err, ErrInvalidGW))
 	}
+	// ProtocolIP is used as the BGP Router ID which is always a 32-bit value
+	if !protoIP.Addr().Is4() {
+		return fmt.Errorf("ProtocolIP %s must be an IPv4 address as it's used as BGP Router ID: %w", gw.Spec.ProtocolIP, ErrInvalidGW)
+	}
 	if protoIP.Bits() != 32 {
 		return fmt.Errorf("ProtocolIP %s must be a /32 prefix: %w", gw.Spec.ProtocolIP, ErrInvalidGW)
 	}
-	if !protoIP.Addr().Is4() {
-		return fmt.Errorf("ProtocolIP %s must be an IPv4 address: %w", gw.Spec.ProtocolIP, ErrInvalidGW)
+
+	var protoIPv6 netip.Prefix
+	if gw.Spec.ProtocolIPv6 != "" {
+		protoIPv6, err = netip.ParsePrefix(gw.Spec.ProtocolIPv6)
+		if err != nil {
+			return fmt.Errorf("invalid ProtocolIPv6 %s: %w", gw.Spec.ProtocolIPv6, errors.Join(err, ErrInvalidGW))
+		}
+		if !protoIPv6.Addr().Is6() || protoIPv6.Addr().Is4In6() {
+			return fmt.Errorf("ProtocolIPv6 %s must be an IPv6 address: %w", gw.Spec.ProtocolIPv6, ErrInvalidGW)
+		}
+		if protoIPv6.Bits() != 128 {
+			return fmt.Errorf("ProtocolIPv6 %s must be a /128 prefix: %w", gw.Spec.ProtocolIPv6, ErrInvalidGW)
+		}
+		if !protoIPv6.Addr().IsGlobalUnicast() {
+			return fmt.Errorf("ProtocolIPv6 %s must be a global unicast IPv6 address: %w", gw.Spec.ProtocolIPv6, ErrInvalidGW)
+		}
 	}
 
 	vtepIP, err := netip.ParsePrefix(gw.Spec.VTEPIP)
 	if err != nil {
 		return fmt.Errorf("invalid VTEPIP %s: %w", gw.Spec.VTEPIP, errors.Join(err, ErrInvalidGW))
 	}
+	// fabric VXLAN underlay is IPv4-only, while IPv6 could still be used inside of the VPCs
+	if !vtepIP.Addr().Is4() {
+		return fmt.Errorf("VTEPIP %s must be an IPv4 address as VXLAN underlay is IPv4-only: %w", gw.Spec.VTEPIP, ErrInvalidGW)
+	}
 	if vtepIP.Bits() != 32 {
 		return fmt.Errorf("VTEPIP %s must be a /32 prefix: %w", gw.Spec.VTEPIP, ErrInvalidGW)
 	}
-	if !vtepIP.Addr().Is4() {
-		return fmt.Errorf("VTEPIP %s must be an IPv4 address: %w", gw.Spec.VTEPIP, ErrInvalidGW)
-	}
 	if vtepIP.Addr().IsMulticast() || vtepIP.Addr().IsLoopback() || vtepIP.Addr().IsUnspecified() {
 		return fmt.Errorf("VTEPIP %s must be a unicast IPv4 address: %w", gw.Spec.VTEPIP, ErrInvalidGW)
 	}
@@ -233,6 +869,25 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 		return fmt.Errorf("at least one interface must be defined: %w", ErrInvalidGW)
 	}
 	pcis, kernels := 0, 0
+	hasIPv4, hasIPv6 := false, gw.Spec.ProtocolIPv6 != ""
+	bondMembers := map[string]string{}
+	vlanParents := map[string]bool{}
+	for name, iface := range gw.Spec.Interfaces {
+		if iface.VLAN != nil {
+			vlanParents[iface.VLAN.Parent] = true
+		}
+
+		if iface.Bond == nil {
+			continue
+		}
+
+		for _, member := range iface.Bond.Members {
+			if other, exist := bondMembers[member]; exist {
+				return fmt.Errorf("interface %s can't be a member of both bonds %s and %s: %w", member, other, name, ErrInvalidGW)
+			}
+			bondMembers[member] = name
+		}
+	}
 	for name, iface := range gw.Spec.Interfaces {
 		if len(name) > 15 {
 			return fmt.Errorf("interface name %s is too long: %w", name, ErrInvalidGW)
@@ -241,16 +896,51 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 			return fmt.Errorf("interface name %s must be a valid linux interface name (2-10 characters): %w", name, ErrInvalidGW)
 		}
 
-		if len(iface.IPs) == 0 {
+		if iface.Bond != nil {
+			if err := validateBond(gw, name, iface); err != nil {
+				return err
+			}
+		}
+		if iface.VLAN != nil {
+			if err := validateVLAN(gw, name, iface, bondMembers); err != nil {
+				return err
+			}
+		}
+
+		if bond, isMember := bondMembers[name]; isMember {
+			if len(iface.IPs) > 0 {
+				return fmt.Errorf("interface %s is a member of bond %s and can't have IP addresses: %w", name, bond, ErrInvalidGW)
+			}
+			if iface.PCI != "" {
+				// TODO enable after dataplane supports bonding for DPDK driver
+				return fmt.Errorf("interface %s is a member of bond %s and can't use PCI address as only kernel bonds are supported: %w", name, bond, ErrInvalidGW)
+			}
+			if iface.MTU != gw.Spec.Interfaces[bond].MTU {
+				return fmt.Errorf("interface %s MTU %d must match bond %s MTU: %w", name, iface.MTU, bond, ErrInvalidGW)
+			}
+		} else if len(iface.IPs) == 0 && !vlanParents[name] {
+			// interfaces with VLAN sub-interfaces could be used as trunks only
 			return fmt.Errorf("interface %s must have at least one IP address: %w", name, ErrInvalidGW)
 		}
-		for _, ifaceIP := range iface.IPs {
-			ifaceIP, err := netip.ParsePrefix(ifaceIP)
+		for _, ifaceIPStr := range iface.IPs {
+			ifaceIP, err := netip.ParsePrefix(ifaceIPStr)
 			if err != nil {
-				return fmt.Errorf("invalid interface %s IP %s: %w", name, ifaceIP, errors.Join(err, ErrInvalidGW))
+				return fmt.Errorf("invalid interface %s IP %s: %w", name, ifaceIPStr, errors.Join(err, ErrInvalidGW))
 			}
-			if !ifaceIP.Addr().Is4() {
-				return fmt.Errorf("interface %s IP %s must be an IPv4 address: %w", name, ifaceIP, ErrInvalidGW)
+			if ifaceIP.Addr().Is4In6() {
+				return fmt.Errorf("interface %s IP %s must not be an IPv4-mapped IPv6 address: %w", name, ifaceIP, ErrInvalidGW)
+			}
+			if ifaceIP.Addr().IsMulticast() || ifaceIP.Addr().IsUnspecified() || ifaceIP.Addr().IsLoopback() {
+				return fmt.Errorf("interface %s IP %s must be a unicast address: %w", name, ifaceIP, ErrInvalidGW)
+			}
+			if ifaceIP.Addr().Is4() {
+				hasIPv4 = true
+			} else {
+				// link-local addresses are assigned by the kernel automatically
+				if ifaceIP.Addr().IsLinkLocalUnicast() {
+					return fmt.Errorf("interface %s IP %s must not be an IPv6 link-local address: %w", name, ifaceIP, ErrInvalidGW)
+				}
+				hasIPv6 = true
 			}
 		}
 
@@ -331,16 +1021,31 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 		if err != nil {
 			return fmt.Errorf("invalid neighbor IP %s: %w", neigh.IP, errors.Join(err, ErrInvalidGW))
 		}
-		if !neighIP.Is4() {
-			return fmt.Errorf("BGP neighbor IP %s must be an IPv4 address: %w", neigh.IP, ErrInvalidGW)
+		if neighIP.Zone() != "" || neighIP.Is4In6() {
+			return fmt.Errorf("BGP neighbor IP %s must be a plain IPv4 or IPv6 address: %w", neigh.IP, ErrInvalidGW)
 		}
-		if neighIP.IsMulticast() || neighIP.IsUnspecified() {
-			return fmt.Errorf("BGP neighbor IP %s must be a unicast IPv4 address: %w", neigh.IP, ErrInvalidGW)
+		if neighIP.IsMulticast() || neighIP.IsUnspecified() || neighIP.IsLoopback() {
+			return fmt.Errorf("BGP neighbor IP %s must be a unicast address: %w", neigh.IP, ErrInvalidGW)
+		}
+		if neighIP.Is4() && !hasIPv4 {
+			return fmt.Errorf("BGP neighbor IP %s is IPv4 but no interface has an IPv4 address: %w", neigh.IP, ErrInvalidGW)
+		}
+		if neighIP.Is6() {
+			if neighIP.IsLinkLocalUnicast() {
+				return fmt.Errorf("BGP neighbor IP %s must not be an IPv6 link-local address: %w", neigh.IP, ErrInvalidGW)
+			}
+			if !hasIPv6 {
+				return fmt.Errorf("BGP neighbor IP %s is IPv6 but no interface has an IPv6 address: %w", neigh.IP, ErrInvalidGW)
+			}
 		}
 
 		if neigh.ASN == 0 {
 			return fmt.Errorf("BGP neighbor %s must have an ASN: %w", neigh.IP, ErrInvalidGW)
 		}
+
+		if err := neigh.validate(gw.Spec.ASN); err != nil {
+			return err
+		}
 	}
 
 	if len(gw.Spec.Groups) == 0 {
@@ -356,9 +1061,21 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 
 	// uniqueness checks
 	if kube != nil {
+		nodes, err := gw.nodes(ctx, kube)
+		if err != nil {
+			return err
+		}
+		// all copies of the gateway would run with the same IPs and ASN
+		if len(nodes) > 1 {
+			return fmt.Errorf("gateway %s node selector %v matches %d nodes (%s), it should match exactly one: %w",
+				gw.Name, gw.Spec.NodeSelector, len(nodes), strings.Join(nodes, ", "), ErrInvalidGW)
+		}
+
 		protocolIPs := map[netip.Addr]bool{}
+		protocolIPv6s := map[netip.Addr]bool{}
 		vtepIPs := map[netip.Addr]bool{}
 		gwGroupMembers := map[string]int{}
+		gwGroupPriorities := map[string]map[uint32]string{}
 		gateways := &GatewayList{}
 		if err := kube.List(ctx, gateways); err != nil {
 			return fmt.Errorf("listing gateways: %w", err)
@@ -373,6 +1090,11 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 					protocolIPs[ip.Addr()] = true
 				}
 			}
+			if other.Spec.ProtocolIPv6 != "" {
+				if ip, err := netip.ParsePrefix(other.Spec.ProtocolIPv6); err == nil {
+					protocolIPv6s[ip.Addr()] = true
+				}
+			}
 			if other.Spec.VTEPIP != "" {
 				if ip, err := netip.ParsePrefix(other.Spec.VTEPIP); err == nil {
 					vtepIPs[ip.Addr()] = true
@@ -380,11 +1102,27 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 			}
 			for _, group := range other.Spec.Groups {
 				gwGroupMembers[group.Name]++
+				if gwGroupPriorities[group.Name] == nil {
+					gwGroupPriorities[group.Name] = map[uint32]string{}
+				}
+				gwGroupPriorities[group.Name][group.Priority] = other.Name
+			}
+			if len(nodes) > 0 {
+				otherNodes, err := other.nodes(ctx, kube)
+				if err != nil {
+					return err
+				}
+				if slices.Contains(otherNodes, nodes[0]) {
+					return fmt.Errorf("gateway %s node %s is already used by gateway %s: %w", gw.Name, nodes[0], other.Name, ErrInvalidGW)
+				}
 			}
 		}
 		if _, exist := protocolIPs[protoIP.Addr()]; exist {
 			return fmt.Errorf("gateway %s protocol IP %s is already in use: %w", gw.Name, protoIP, ErrInvalidGW)
 		}
+		if protoIPv6.IsValid() && protocolIPv6s[protoIPv6.Addr()] {
+			return fmt.Errorf("gateway %s protocol IPv6 %s is already in use: %w", gw.Name, protoIPv6, ErrInvalidGW)
+		}
 		if _, exist := vtepIPs[vtepIP.Addr()]; exist {
 			return fmt.Errorf("gateway %s VTEP IP %s is already in use: %w", gw.Name, vtepIP, ErrInvalidGW)
 		}
@@ -393,14 +1131,24 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 		if err := kube.List(ctx, gwGroupList, kclient.InNamespace(kmetav1.NamespaceDefault)); err != nil {
 			return fmt.Errorf("listing gateway groups: %w", err)
 		}
-		gwGroups := map[string]bool{}
+		gwGroups := map[string]*GatewayGroup{}
 		for _, gwGroup := range gwGroupList.Items {
-			gwGroups[gwGroup.Name] = true
+			gwGroup.Default()
+			gwGroups[gwGroup.Name] = &gwGroup
 		}
 		for _, gwGroup := range gw.Spec.Groups {
-			if !gwGroups[gwGroup.Name] {
+			group, exists := gwGroups[gwGroup.Name]
+			if !exists {
 				return fmt.Errorf("gateway group %s not found: %w", gwGroup.Name, ErrInvalidGW)
 			}
+			if gwGroup.Weight > 0 && group.Spec.Redundancy.Mode != GatewayGroupModeECMP {
+				return fmt.Errorf("gateway group %s weight is only supported in %s mode: %w", gwGroup.Name, GatewayGroupModeECMP, ErrInvalidGW)
+			}
+			// priority defines the active member so it has to be unambiguous, it's ignored in ecmp mode and the
+			// default one (0) could be shared by all gateways that don't set it, e.g. in the default group
+			if other, exists := gwGroupPriorities[gwGroup.Name][gwGroup.Priority]; exists && gwGroup.Priority != 0 && group.Spec.Redundancy.Mode != GatewayGroupModeECMP {
+				return fmt.Errorf("gateway group %s priority %d is already used by gateway %s: %w", gwGroup.Name, gwGroup.Priority, other, ErrInvalidGW)
+			}
 			if gwCfg != nil && len(gwCfg.Communities) > 0 && gwGroupMembers[gwGroup.Name] >= len(gwCfg.Communities) {
 				return fmt.Errorf("gateway group %s already has too many members (%d), max is %d: %w", gwGroup.Name, gwGroupMembers[gwGroup.Name], len(gwCfg.Communities), ErrInvalidGW)
 			}
@@ -409,3 +1157,410 @@ func (gw *Gateway) Validate(ctx context.Context, kube kclient.Reader, gwCfg *met
 
 	return nil
 }
+
+func (neigh *GatewayBGPNeighbor) validate(localASN uint32) error {
+	if neigh.Auth != nil {
+		if !slices.Contains(GatewayBGPAuthTypes, neigh.Auth.Type) {
+			return fmt.Errorf("BGP neighbor %s auth type %q must be one of %v: %w", neigh.IP, neigh.Auth.Type, GatewayBGPAuthTypes, ErrInvalidGW)
+		}
+		if neigh.Auth.SecretRef.Name == "" || neigh.Auth.SecretRef.Key == "" {
+			return fmt.Errorf("BGP neighbor %s auth must reference a secret name and key: %w", neigh.IP, ErrInvalidGW)
+		}
+		if neigh.Auth.Type == GatewayBGPAuthTypeTCPAO {
+			if !slices.Contains(GatewayBGPAuthAlgorithms, neigh.Auth.Algorithm) {
+				return fmt.Errorf("BGP neighbor %s TCP-AO algorithm %q must be one of %v: %w", neigh.IP, neigh.Auth.Algorithm, GatewayBGPAuthAlgorithms, ErrInvalidGW)
+			}
+		} else if neigh.Auth.Algorithm != "" || neigh.Auth.SendID != 0 || neigh.Auth.RecvID != 0 {
+			return fmt.Errorf("BGP neighbor %s algorithm and key IDs are only supported with TCP-AO: %w", neigh.IP, ErrInvalidGW)
+		}
+	}
+
+	if neigh.Timers != nil {
+		if neigh.Timers.Hold != 0 && neigh.Timers.Hold < 3 {
+			return fmt.Errorf("BGP neighbor %s hold time must be 0 or at least 3 seconds: %w", neigh.IP, ErrInvalidGW)
+		}
+		if neigh.Timers.Hold != 0 && neigh.Timers.Keepalive >= neigh.Timers.Hold {
+			return fmt.Errorf("BGP neighbor %s keepalive must be less than hold time: %w", neigh.IP, ErrInvalidGW)
+		}
+		if neigh.Timers.Keepalive != 0 && neigh.Timers.Hold == 0 {
+			return fmt.Errorf("BGP neighbor %s keepalive requires a non-zero hold time: %w", neigh.IP, ErrInvalidGW)
+		}
+	}
+
+	if neigh.BFD != nil {
+		if !neigh.BFD.Enabled && (neigh.BFD.DetectMultiplier != 0 || neigh.BFD.ReceiveInterval != 0 || neigh.BFD.TransmitInterval != 0) {
+			return fmt.Errorf("BGP neighbor %s BFD parameters can only be set when BFD is enabled: %w", neigh.IP, ErrInvalidGW)
+		}
+		if neigh.BFD.Enabled {
+			if neigh.BFD.DetectMultiplier < 2 {
+				return fmt.Errorf("BGP neighbor %s BFD detect multiplier must be between 2 and 255: %w", neigh.IP, ErrInvalidGW)
+			}
+			if neigh.BFD.ReceiveInterval < 10 || neigh.BFD.ReceiveInterval > 60000 {
+				return fmt.Errorf("BGP neighbor %s BFD receive interval must be between 10 and 60000 ms: %w", neigh.IP, ErrInvalidGW)
+			}
+			if neigh.BFD.TransmitInterval < 10 || neigh.BFD.TransmitInterval > 60000 {
+				return fmt.Errorf("BGP neighbor %s BFD transmit interval must be between 10 and 60000 ms: %w", neigh.IP, ErrInvalidGW)
+			}
+		}
+	}
+
+	if neigh.EBGPMultihop != 0 && neigh.ASN == localASN {
+		return fmt.Errorf("BGP neighbor %s eBGP multihop can't be used with iBGP neighbors: %w", neigh.IP, ErrInvalidGW)
+	}
+
+	usedAFs := map[GatewayBGPAddressFamily]bool{}
+	for _, af := range neigh.AddressFamilies {
+		if !slices.Contains(GatewayBGPAddressFamilies, af) {
+			return fmt.Errorf("BGP neighbor %s address family %q must be one of %v: %w", neigh.IP, af, GatewayBGPAddressFamilies, ErrInvalidGW)
+		}
+		if usedAFs[af] {
+			return fmt.Errorf("BGP neighbor %s address family %s is duplicate: %w", neigh.IP, af, ErrInvalidGW)
+		}
+		usedAFs[af] = true
+	}
+
+	return nil
+}
+
+func validateBond(gw *Gateway, name string, iface GatewayInterface) error {
+	bond := iface.Bond
+
+	if iface.PCI != "" || iface.Kernel != "" {
+		return fmt.Errorf("bond interface %s can't have PCI address or kernel name: %w", name, ErrInvalidGW)
+	}
+	if len(bond.Members) == 0 {
+		return fmt.Errorf("bond interface %s must have at least one member: %w", name, ErrInvalidGW)
+	}
+	for idx, memberName := range bond.Members {
+		if slices.Contains(bond.Members[:idx], memberName) {
+			return fmt.Errorf("bond interface %s member %s is duplicate: %w", name, memberName, ErrInvalidGW)
+		}
+
+		member, exists := gw.Spec.Interfaces[memberName]
+		if !exists {
+			return fmt.Errorf("bond interface %s member %s not found: %w", name, memberName, ErrInvalidGW)
+		}
+		if member.Bond != nil || member.VLAN != nil {
+			return fmt.Errorf("bond interface %s member %s can't be a bond or VLAN interface: %w", name, memberName, ErrInvalidGW)
+		}
+	}
+	for _, neigh := range gw.Spec.Neighbors {
+		if slices.Contains(bond.Members, neigh.Source) {
+			return fmt.Errorf("bond interface %s member %s can't be used as BGP neighbor %s source: %w", name, neigh.Source, neigh.IP, ErrInvalidGW)
+		}
+	}
+
+	if !slices.Contains(GatewayBondLACPModes, bond.LACPMode) {
+		return fmt.Errorf("bond interface %s LACP mode %q must be one of %v: %w", name, bond.LACPMode, GatewayBondLACPModes, ErrInvalidGW)
+	}
+	if !slices.Contains(GatewayBondLACPRates, bond.LACPRate) {
+		return fmt.Errorf("bond interface %s LACP rate %q must be one of %v: %w", name, bond.LACPRate, GatewayBondLACPRates, ErrInvalidGW)
+	}
+	if !slices.Contains(GatewayBondHashPolicies, bond.HashPolicy) {
+		return fmt.Errorf("bond interface %s hash policy %q must be one of %v: %w", name, bond.HashPolicy, GatewayBondHashPolicies, ErrInvalidGW)
+	}
+
+	return nil
+}
+
+func validateVLAN(gw *Gateway, name string, iface GatewayInterface, bondMembers map[string]string) error {
+	vlan := iface.VLAN
+
+	if iface.PCI != "" || iface.Kernel != "" || iface.Bond != nil {
+		return fmt.Errorf("VLAN interface %s can't have PCI address, kernel name or bond: %w", name, ErrInvalidGW)
+	}
+	if vlan.ID < 1 || vlan.ID > 4094 {
+		return fmt.Errorf("VLAN interface %s VLAN ID %d must be between 1 and 4094: %w", name, vlan.ID, ErrInvalidGW)
+	}
+
+	parent, exists := gw.Spec.Interfaces[vlan.Parent]
+	if !exists {
+		return fmt.Errorf("VLAN interface %s parent %s not found: %w", name, vlan.Parent, ErrInvalidGW)
+	}
+	if parent.VLAN != nil {
+		return fmt.Errorf("VLAN interface %s parent %s can't be a VLAN interface itself: %w", name, vlan.Parent, ErrInvalidGW)
+	}
+	if bond, isMember := bondMembers[vlan.Parent]; isMember {
+		return fmt.Errorf("VLAN interface %s parent %s is a member of bond %s, use the bond as parent: %w", name, vlan.Parent, bond, ErrInvalidGW)
+	}
+	if parent.PCI != "" {
+		// TODO enable after dataplane supports VLAN sub-interfaces for DPDK driver
+		return fmt.Errorf("VLAN interface %s parent %s can't use PCI address as only kernel VLANs are supported: %w", name, vlan.Parent, ErrInvalidGW)
+	}
+	if parent.MTU != 0 && iface.MTU > parent.MTU {
+		return fmt.Errorf("VLAN interface %s MTU %d must not exceed parent %s MTU %d: %w", name, iface.MTU, vlan.Parent, parent.MTU, ErrInvalidGW)
+	}
+
+	for otherName, other := range gw.Spec.Interfaces {
+		if otherName != name && other.VLAN != nil && other.VLAN.Parent == vlan.Parent && other.VLAN.ID == vlan.ID {
+			return fmt.Errorf("VLAN interface %s VLAN ID %d is already used by %s on parent %s: %w", name, vlan.ID, otherName, vlan.Parent, ErrInvalidGW)
+		}
+	}
+
+	return nil
+}
+
+func (res *GatewayDataplaneResources) validate(workers uint8) error {
+	if res.CPUs > 0 && uint16(workers) > res.CPUs {
+		return fmt.Errorf("workers %d don't fit into %d CPUs: %w", workers, res.CPUs, ErrInvalidGW)
+	}
+
+	if res.NUMANode != nil && *res.NUMANode > 63 {
+		return fmt.Errorf("NUMA node %d must be between 0 and 63: %w", *res.NUMANode, ErrInvalidGW)
+	}
+
+	if res.CPUSet != "" {
+		cpus, err := parseCPUSet(res.CPUSet)
+		if err != nil {
+			return fmt.Errorf("invalid CPU set %q: %w", res.CPUSet, errors.Join(err, ErrInvalidGW))
+		}
+		if int(workers) > len(cpus) {
+			return fmt.Errorf("workers %d don't fit into %d CPUs of CPU set %q: %w", workers, len(cpus), res.CPUSet, ErrInvalidGW)
+		}
+	}
+
+	if res.Memory != "" {
+		mem, err := resource.ParseQuantity(res.Memory)
+		if err != nil {
+			return fmt.Errorf("invalid memory %q: %w", res.Memory, errors.Join(err, ErrInvalidGW))
+		}
+		if mem.Sign() <= 0 {
+			return fmt.Errorf("memory %q must be positive: %w", res.Memory, ErrInvalidGW)
+		}
+	}
+
+	if res.HugepageSize != "" {
+		if !slices.Contains(GatewayHugepageSizes, res.HugepageSize) {
+			return fmt.Errorf("invalid hugepage size %q, must be one of %v: %w", res.HugepageSize, GatewayHugepageSizes, ErrInvalidGW)
+		}
+		if res.Hugepages == 0 {
+			return fmt.Errorf("hugepage size is set without the number of hugepages: %w", ErrInvalidGW)
+		}
+	}
+
+	return nil
+}
+
+// parseCPUSet parses a list of CPUs in the Linux cpuset format (e.g. 0-3,8,10-11) into the sorted list of CPUs
+func parseCPUSet(in string) ([]uint16, error) {
+	cpus := []uint16{}
+	seen := map[uint16]bool{}
+	for part := range strings.SplitSeq(in, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+
+		from, err := strconv.ParseUint(first, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("parsing CPU %q: %w", first, err)
+		}
+		to := from
+		if isRange {
+			to, err = strconv.ParseUint(last, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("parsing CPU %q: %w", last, err)
+			}
+		}
+		if from > to {
+			return nil, fmt.Errorf("invalid CPU range %q", part) //nolint:err113
+		}
+		if to > MaxCPUSetCPU {
+			return nil, fmt.Errorf("CPU %d is out of range, max is %d", to, MaxCPUSetCPU) //nolint:err113
+		}
+
+		for cpu := from; cpu <= to; cpu++ {
+			if seen[uint16(cpu)] {
+				return nil, fmt.Errorf("duplicate CPU %d", cpu) //nolint:err113
+			}
+			seen[uint16(cpu)] = true
+			cpus = append(cpus, uint16(cpu))
+		}
+	}
+	slices.Sort(cpus)
+
+	return cpus, nil
+}
+
+var profilingTagRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func (prof *GatewayProfiling) validate() error {
+	if prof.URL != "" {
+		if err := meta.ValidateProfilingURL(prof.URL); err != nil {
+			return fmt.Errorf("invalid profiling URL: %w", errors.Join(err, ErrInvalidGW))
+		}
+	}
+
+	if prof.SampleRate > MaxProfilingSampleRate {
+		return fmt.Errorf("profiling sample rate %d is above max %d: %w", prof.SampleRate, MaxProfilingSampleRate, ErrInvalidGW)
+	}
+
+	for key, value := range prof.Tags {
+		if !profilingTagRegex.MatchString(key) || strings.HasPrefix(key, "__") {
+			return fmt.Errorf("invalid profiling tag name %q: %w", key, ErrInvalidGW)
+		}
+		if value == "" {
+			return fmt.Errorf("profiling tag %q value must not be empty: %w", key, ErrInvalidGW)
+		}
+	}
+
+	for idx, profType := range prof.ProfileTypes {
+		if !slices.Contains(GatewayProfileTypes, profType) {
+			return fmt.Errorf("invalid profile type %q, must be one of %v: %w", profType, GatewayProfileTypes, ErrInvalidGW)
+		}
+		if slices.Contains(prof.ProfileTypes[:idx], profType) {
+			return fmt.Errorf("duplicate profile type %q: %w", profType, ErrInvalidGW)
+		}
+	}
+
+	return nil
+}
+
+// GatewayConfirmAnnotation has to be set to "true" on the gateway in the same update that disrupts the fabric to
+// confirm it, it's removed by the controller once the update is processed and disruptive updates made before that
+// are rejected, so each of them has to be confirmed separately
+const GatewayConfirmAnnotation = "gateway.githedgehog.com/confirm-disruptive-update"
+
+// Driver returns the dataplane driver used for the gateway interfaces, dpdk if any of them has PCI address set
+func (spec *GatewaySpec) Driver() string {
+	for _, iface := range spec.Interfaces {
+		if iface.PCI != "" {
+			return "dpdk"
+		}
+	}
+
+	return "kernel"
+}
+
+func ifaceKind(iface GatewayInterface) string {
+	switch {
+	case iface.Bond != nil:
+		return "bond"
+	case iface.VLAN != nil:
+		return "vlan"
+	case iface.PCI != "":
+		return "pci"
+	default:
+		return "kernel"
+	}
+}
+
+// ValidateUpdate checks the transition from the old gateway and returns warnings for the changes that are allowed but
+// affect traffic. Changing the driver or interface types is not allowed at all and changing the gateway identity
+// (IPs, MAC, ASN) or interface bindings requires confirmation using the GatewayConfirmAnnotation.
+func (gw *Gateway) ValidateUpdate(old *Gateway) ([]string, error) {
+	// old object could be stored before some defaults were introduced, e.g. IP canonicalization
+	old = old.DeepCopy()
+	old.Default()
+	oldSpec, newSpec := &old.Spec, &gw.Spec
+
+	if oldDriver, newDriver := oldSpec.Driver(), newSpec.Driver(); oldDriver != newDriver {
+		return nil, fmt.Errorf("interface driver can't be changed from %s to %s, re-create the gateway instead: %w", oldDriver, newDriver, ErrInvalidGW)
+	}
+
+	disruptive := []string{}
+	for _, field := range []struct {
+		name     string
+		old, new any
+	}{
+		{"protocolIP", oldSpec.ProtocolIP, newSpec.ProtocolIP},
+		{"protocolIPv6", oldSpec.ProtocolIPv6, newSpec.ProtocolIPv6},
+		{"vtepIP", oldSpec.VTEPIP, newSpec.VTEPIP},
+		{"vtepMAC", oldSpec.VTEPMAC, newSpec.VTEPMAC},
+		{"asn", oldSpec.ASN, newSpec.ASN},
+	} {
+		if field.old != field.new {
+			disruptive = append(disruptive, field.name)
+		}
+	}
+
+	warnings := []string{}
+	for _, name := range slices.Sorted(maps.Keys(oldSpec.Interfaces)) {
+		oldIface := oldSpec.Interfaces[name]
+		newIface, exists := newSpec.Interfaces[name]
+		if !exists {
+			disruptive = append(disruptive, fmt.Sprintf("interface %s removal", name))
+
+			continue
+		}
+
+		if oldKind, newKind := ifaceKind(oldIface), ifaceKind(newIface); oldKind != newKind {
+			return nil, fmt.Errorf("interface %s type can't be changed from %s to %s, remove and add it under a new name instead: %w", name, oldKind, newKind, ErrInvalidGW)
+		}
+		if oldIface.PCI != newIface.PCI || oldIface.Kernel != newIface.Kernel {
+			disruptive = append(disruptive, fmt.Sprintf("interface %s binding", name))
+		}
+		if !reflect.DeepEqual(oldIface.Bond, newIface.Bond) || !reflect.DeepEqual(oldIface.VLAN, newIface.VLAN) {
+			disruptive = append(disruptive, fmt.Sprintf("interface %s bond or VLAN config", name))
+		}
+		if !slices.Equal(oldIface.IPs, newIface.IPs) || oldIface.MTU != newIface.MTU {
+			warnings = append(warnings, fmt.Sprintf("interface %s addresses or MTU changed, traffic over it will be interrupted", name))
+		}
+	}
+
+	if len(disruptive) > 0 {
+		if gw.Annotations[GatewayConfirmAnnotation] != "true" {
+			return nil, fmt.Errorf("changing %s disrupts the fabric, set annotation %s=true to confirm: %w",
+				strings.Join(disruptive, ", "), GatewayConfirmAnnotation, ErrInvalidGW)
+		}
+		// confirmation left from the previous update isn't removed by the controller yet, so it doesn't cover this one
+		if old.Annotations[GatewayConfirmAnnotation] == "true" {
+			return nil, fmt.Errorf("changing %s disrupts the fabric but annotation %s is left from the previous update, wait for it to be removed and confirm again: %w",
+				strings.Join(disruptive, ", "), GatewayConfirmAnnotation, ErrInvalidGW)
+		}
+
+		warnings = append(warnings, "confirmed disruptive change of "+strings.Join(disruptive, ", "))
+	}
+
+	if !reflect.DeepEqual(oldSpec.Neighbors, newSpec.Neighbors) {
+		warnings = append(warnings, "BGP neighbors changed, affected sessions will be reset")
+	}
+	if oldSpec.Workers != newSpec.Workers || !reflect.DeepEqual(oldSpec.Resources, newSpec.Resources) ||
+		oldSpec.Images != newSpec.Images {
+		warnings = append(warnings, "dataplane workers, resources or images changed, gateway pods will be restarted")
+	}
+	if old.Node() != gw.Node() || !maps.Equal(oldSpec.NodeSelector, newSpec.NodeSelector) {
+		warnings = append(warnings, "node placement changed, gateway will be moved to another node")
+	}
+
+	return warnings, nil
+}
+
+// ValidateDelete rejects deleting the last gateway of the groups that still have peerings
+func (gw *Gateway) ValidateDelete(ctx context.Context, kube kclient.Reader) ([]string, error) {
+	gws := &GatewayList{}
+	if err := kube.List(ctx, gws, kclient.InNamespace(gw.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing gateways: %w", err)
+	}
+
+	orphaned := map[string]bool{}
+	for _, membership := range gw.Spec.Groups {
+		orphaned[membership.Name] = true
+	}
+	for _, other := range gws.Items {
+		if other.Name == gw.Name {
+			continue
+		}
+		for _, membership := range other.Spec.Groups {
+			delete(orphaned, membership.Name)
+		}
+	}
+	if len(orphaned) == 0 {
+		return nil, nil
+	}
+
+	peerings := &PeeringList{}
+	if err := kube.List(ctx, peerings, kclient.InNamespace(gw.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing peerings: %w", err)
+	}
+
+	dependents := map[string][]string{}
+	usedGroups := map[string]bool{}
+	for _, peering := range peerings.Items {
+		// auto placed peerings depend on the group they're placed to
+		if gwGroup := peering.AssignedGatewayGroup(); orphaned[gwGroup] {
+			dependents["peerings"] = append(dependents["peerings"], peering.Name)
+			usedGroups[gwGroup] = true
+		}
+	}
+
+	return checkDependents(gw, fmt.Sprintf("gateway %s as the last member of gateway groups %s", gw.Name,
+		strings.Join(slices.Sorted(maps.Keys(usedGroups)), ", ")), dependents)
+}
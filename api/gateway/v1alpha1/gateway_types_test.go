@@ -11,8 +11,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.githedgehog.com/gateway/api/gateway/v1alpha1"
 	"go.githedgehog.com/gateway/api/meta"
+	corev1 "k8s.io/api/core/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -56,6 +58,10 @@ func gwa(name string, f ...func(gw *v1alpha1.Gateway)) *v1alpha1.Gateway {
 	return gw
 }
 
+func node(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: kmetav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
 func withObjs(base []kclient.Object, objs ...kclient.Object) []kclient.Object {
 	return append(slices.Clone(base), objs...)
 }
@@ -195,6 +201,528 @@ func TestGatewayValidate(t *testing.T) {
 			objs: base,
 			err:  v1alpha1.ErrInvalidGW,
 		},
+		{
+			name: "test-interface-ipv6",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0"] = v1alpha1.GatewayInterface{Kernel: "eth0", IPs: []string{"172.30.128.3/31", "fd00:128::3/127"}, MTU: 1500}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-interface-ipv6-link-local",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0"] = v1alpha1.GatewayInterface{Kernel: "eth0", IPs: []string{"172.30.128.3/31", "fe80::3/64"}, MTU: 1500}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-ipv6",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0"] = v1alpha1.GatewayInterface{Kernel: "eth0", IPs: []string{"fd00:128::3/127"}, MTU: 1500}
+				gw.Spec.Neighbors[0].IP = "fd00:128::2"
+			}),
+			objs: base,
+		},
+		{
+			name: "test-neighbor-ipv6-without-ipv6-interface",
+			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.Neighbors[0].IP = "fd00:128::2" }),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-ipv4-without-ipv4-interface",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0"] = v1alpha1.GatewayInterface{Kernel: "eth0", IPs: []string{"fd00:128::3/127"}, MTU: 1500}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-ipv6-link-local",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0"] = v1alpha1.GatewayInterface{Kernel: "eth0", IPs: []string{"fd00:128::3/127"}, MTU: 1500}
+				gw.Spec.Neighbors[0].IP = "fe80::2"
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-full",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Neighbors[0].Auth = &v1alpha1.GatewayBGPNeighborAuth{
+					Type:      v1alpha1.GatewayBGPAuthTypeTCPAO,
+					SecretRef: v1alpha1.GatewaySecretKeyRef{Name: "bgp", Key: "spine"},
+					SendID:    1,
+					RecvID:    2,
+				}
+				gw.Spec.Neighbors[0].Timers = &v1alpha1.GatewayBGPNeighborTimers{Keepalive: 3, Hold: 9}
+				gw.Spec.Neighbors[0].BFD = &v1alpha1.GatewayBGPNeighborBFD{Enabled: true}
+				gw.Spec.Neighbors[0].EBGPMultihop = 2
+				gw.Spec.Neighbors[0].AddressFamilies = []v1alpha1.GatewayBGPAddressFamily{
+					v1alpha1.GatewayBGPAddressFamilyIPv4Unicast,
+					v1alpha1.GatewayBGPAddressFamilyL2VPNEVPN,
+				}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-neighbor-auth-no-secret",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Neighbors[0].Auth = &v1alpha1.GatewayBGPNeighborAuth{Type: v1alpha1.GatewayBGPAuthTypeTCPMD5}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-auth-md5-with-key-ids",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Neighbors[0].Auth = &v1alpha1.GatewayBGPNeighborAuth{
+					Type:      v1alpha1.GatewayBGPAuthTypeTCPMD5,
+					SecretRef: v1alpha1.GatewaySecretKeyRef{Name: "bgp", Key: "spine"},
+					SendID:    1,
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-keepalive-over-hold",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Neighbors[0].Timers = &v1alpha1.GatewayBGPNeighborTimers{Keepalive: 10, Hold: 9}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-bfd-invalid-interval",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Neighbors[0].BFD = &v1alpha1.GatewayBGPNeighborBFD{Enabled: true, ReceiveInterval: 5}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-ibgp-multihop",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Neighbors[0].ASN = gw.Spec.ASN
+				gw.Spec.Neighbors[0].EBGPMultihop = 2
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-neighbor-duplicate-af",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Neighbors[0].AddressFamilies = []v1alpha1.GatewayBGPAddressFamily{
+					v1alpha1.GatewayBGPAddressFamilyL2VPNEVPN,
+					v1alpha1.GatewayBGPAddressFamilyL2VPNEVPN,
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-proto-ipv6",
+			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.ProtocolIPv6 = "fd00:8::3/128" }),
+			objs: base,
+		},
+		{
+			name: "test-proto-ipv6-non-128",
+			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.ProtocolIPv6 = "fd00:8::3/64" }),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-proto-ipv6-is-v4",
+			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.ProtocolIPv6 = "172.30.8.4/32" }),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-bond",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+					"bond0": {
+						IPs:  []string{"172.30.128.3/31"},
+						MTU:  9000,
+						Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port1", "port0"}},
+					},
+					"port0": {Kernel: "eth0"},
+					"port1": {Kernel: "eth1"},
+				}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-bond-member-with-ips",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+					"bond0": {
+						IPs:  []string{"172.30.128.3/31"},
+						Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port0", "port1"}},
+					},
+					"port0": {Kernel: "eth0", IPs: []string{"172.30.128.5/31"}},
+					"port1": {Kernel: "eth1"},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-bond-member-not-found",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+					"bond0": {
+						IPs:  []string{"172.30.128.3/31"},
+						Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port0", "port1"}},
+					},
+					"port0": {Kernel: "eth0"},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-bond-member-in-two-bonds",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+					"bond0": {
+						IPs:  []string{"172.30.128.3/31"},
+						Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port0"}},
+					},
+					"bond1": {
+						IPs:  []string{"172.30.128.5/31"},
+						Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port0"}},
+					},
+					"port0": {Kernel: "eth0"},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-bond-member-mtu-mismatch",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+					"bond0": {
+						IPs:  []string{"172.30.128.3/31"},
+						MTU:  9000,
+						Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port0"}},
+					},
+					"port0": {Kernel: "eth0", MTU: 1500},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-bond-member-pci",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+					"bond0": {
+						IPs:  []string{"172.30.128.3/31"},
+						Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port0"}},
+					},
+					"port0": {PCI: "0000:02:00.0"},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-vlan",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+					"port0":     {Kernel: "eth0", MTU: 9000},
+					"port0.100": {IPs: []string{"172.30.128.3/31"}, VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port0", ID: 100}},
+					"port0.200": {IPs: []string{"172.30.129.3/31"}, MTU: 1500, VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port0", ID: 200}},
+				}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-vlan-parent-not-found",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0.100"] = v1alpha1.GatewayInterface{
+					IPs:  []string{"172.30.129.3/31"},
+					VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port1", ID: 100},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-vlan-invalid-id",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0.100"] = v1alpha1.GatewayInterface{
+					IPs:  []string{"172.30.129.3/31"},
+					VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port0", ID: 4095},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-vlan-duplicate-id",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0.100"] = v1alpha1.GatewayInterface{
+					IPs:  []string{"172.30.129.3/31"},
+					VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port0", ID: 100},
+				}
+				gw.Spec.Interfaces["port0.101"] = v1alpha1.GatewayInterface{
+					IPs:  []string{"172.30.130.3/31"},
+					VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port0", ID: 100},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-vlan-mtu-over-parent",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0.100"] = v1alpha1.GatewayInterface{
+					IPs:  []string{"172.30.129.3/31"},
+					MTU:  9000,
+					VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port0", ID: 100},
+				}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-resources",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Workers = 4
+				gw.Spec.Resources = v1alpha1.GatewayDataplaneResources{
+					CPUs:      5,
+					CPUSet:    "2-5,10",
+					NUMANode:  ptr.To(uint8(1)),
+					Hugepages: 1024,
+				}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-resources-workers-over-cpus",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Workers = 4
+				gw.Spec.Resources.CPUs = 2
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-resources-workers-over-cpu-set",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Workers = 4
+				gw.Spec.Resources.CPUSet = "2-4"
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-resources-invalid-cpu-set",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Resources.CPUSet = "2-5,4"
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-resources-invalid-numa-node",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Resources.NUMANode = ptr.To(uint8(64))
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-resources-invalid-memory",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Resources.Memory = "lots"
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-resources-invalid-hugepage-size",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Resources.Hugepages = 4
+				gw.Spec.Resources.HugepageSize = "4Ki"
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-node-name",
+			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.NodeName = "node-1" }),
+			objs: base,
+		},
+		{
+			name: "test-node-selector",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.NodeSelector = map[string]string{"node-role.githedgehog.com/gateway": "gw-1"}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-node-name-and-selector",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.NodeName = "node-1"
+				gw.Spec.NodeSelector = map[string]string{"node-role.githedgehog.com/gateway": "gw-1"}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-node-invalid-name",
+			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.NodeName = "Node_1" }),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-node-invalid-selector",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.NodeSelector = map[string]string{"gateway": "not a label value"}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-node-selector-multiple-nodes",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.NodeSelector = map[string]string{"node-role.githedgehog.com/gateway": "true"}
+			}),
+			objs: withObjs(base,
+				node("node-1", map[string]string{"node-role.githedgehog.com/gateway": "true"}),
+				node("node-2", map[string]string{"node-role.githedgehog.com/gateway": "true"}),
+			),
+			err: v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-node-selector-single-node",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.NodeSelector = map[string]string{"node-role.githedgehog.com/gateway": "gw-1"}
+			}),
+			objs: withObjs(base,
+				node("node-1", map[string]string{"node-role.githedgehog.com/gateway": "gw-1"}),
+				node("node-2", map[string]string{"node-role.githedgehog.com/gateway": "gw-2"}),
+			),
+		},
+		{
+			name: "test-node-selector-already-used",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.NodeSelector = map[string]string{"node-role.githedgehog.com/gateway": "gw-1"}
+			}),
+			objs: withObjs(base,
+				node("gw-2", map[string]string{"node-role.githedgehog.com/gateway": "gw-1"}),
+			),
+			err: v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-node-already-used",
+			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.NodeName = "gw-2" }),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-profiling",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Profiling = v1alpha1.GatewayProfiling{
+					Enabled:      true,
+					URL:          "http://alloy-gw.fab.svc.cluster.local:4040",
+					Tags:         map[string]string{"env": "lab"},
+					ProfileTypes: []v1alpha1.GatewayProfileType{v1alpha1.GatewayProfileTypeAlloc, v1alpha1.GatewayProfileTypeCPU},
+				}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-profiling-invalid-url",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Profiling = v1alpha1.GatewayProfiling{Enabled: true, URL: "alloy-gw:4040"}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-profiling-invalid-tag",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Profiling = v1alpha1.GatewayProfiling{Enabled: true, Tags: map[string]string{"some-tag": "value"}}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-profiling-invalid-type",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Profiling = v1alpha1.GatewayProfiling{Enabled: true, ProfileTypes: []v1alpha1.GatewayProfileType{"heap"}}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-profiling-sample-rate-too-high",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Profiling = v1alpha1.GatewayProfiling{Enabled: true, SampleRate: 10000}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-images",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Images.Dataplane = "registry.local:31000/githedgehog/dataplane:v0.1.0-canary"
+			}),
+			objs: base,
+		},
+		{
+			name: "test-images-invalid-ref",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Images.FRR = "registry.local/frr:v1 latest"
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-drain",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Drain = v1alpha1.GatewayDrain{Enabled: true}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-drain-community",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Drain = v1alpha1.GatewayDrain{Enabled: true, Mode: v1alpha1.GatewayDrainModeCommunity}
+			}),
+			objs: base,
+		},
+		{
+			name: "test-drain-invalid-mode",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Drain = v1alpha1.GatewayDrain{Enabled: true, Mode: "withdraw"}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-drain-prepend-too-long",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Drain = v1alpha1.GatewayDrain{Enabled: true, Prepend: 20}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-drain-prepend-in-community-mode",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Drain = v1alpha1.GatewayDrain{Enabled: true, Mode: v1alpha1.GatewayDrainModeCommunity, Prepend: 2}
+			}),
+			objs: base,
+			err:  v1alpha1.ErrInvalidGW,
+		},
 		{
 			name: "test-no-neighbors",
 			gw:   *gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.Neighbors = []v1alpha1.GatewayBGPNeighbor{} }),
@@ -246,11 +774,76 @@ func TestGatewayValidate(t *testing.T) {
 					},
 				}),
 			),
+		}, {
+			name: "test-weight-in-ecmp-group",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Groups = []v1alpha1.GatewayGroupMembership{{Name: "gr1", Weight: 3}}
+			}),
+			objs: withObjs(base,
+				withName("gr1", &v1alpha1.GatewayGroup{Spec: v1alpha1.GatewayGroupSpec{
+					Redundancy: v1alpha1.GatewayGroupRedundancy{Mode: v1alpha1.GatewayGroupModeECMP},
+				}}),
+			),
+		},
+		{
+			name: "test-weight-in-active-standby-group",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Groups = []v1alpha1.GatewayGroupMembership{{Name: "gr1", Weight: 3}}
+			}),
+			objs: withObjs(base, withName("gr1", &v1alpha1.GatewayGroup{})),
+			err:  v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-duplicate-priority-in-active-standby-group",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Groups = []v1alpha1.GatewayGroupMembership{{Name: "gr1", Priority: 1}}
+			}),
+			objs: withObjs(base,
+				withName("gr1", &v1alpha1.GatewayGroup{}),
+				withName("gw-3", &v1alpha1.Gateway{
+					Spec: v1alpha1.GatewaySpec{
+						Groups: []v1alpha1.GatewayGroupMembership{{Name: "gr1", Priority: 1}},
+					},
+				}),
+			),
+			err: v1alpha1.ErrInvalidGW,
+		},
+		{
+			name: "test-defaulted-default-group-membership",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Groups = nil
+				gw.Default()
+			}),
+			objs: withObjs(base,
+				withName("gw-3", func() *v1alpha1.Gateway {
+					gw := &v1alpha1.Gateway{}
+					gw.Default()
+
+					return gw
+				}()),
+			),
+		},
+		{
+			name: "test-duplicate-priority-in-ecmp-group",
+			gw: *gwa("gw-1", func(gw *v1alpha1.Gateway) {
+				gw.Spec.Groups = []v1alpha1.GatewayGroupMembership{{Name: "gr1", Priority: 1}}
+			}),
+			objs: withObjs(base,
+				withName("gr1", &v1alpha1.GatewayGroup{Spec: v1alpha1.GatewayGroupSpec{
+					Redundancy: v1alpha1.GatewayGroupRedundancy{Mode: v1alpha1.GatewayGroupModeECMP},
+				}}),
+				withName("gw-3", &v1alpha1.Gateway{
+					Spec: v1alpha1.GatewaySpec{
+						Groups: []v1alpha1.GatewayGroupMembership{{Name: "gr1", Priority: 1}},
+					},
+				}),
+			),
 		},
 	}
 
 	scheme := runtime.NewScheme()
 	require.NoError(t, v1alpha1.AddToScheme(scheme), "should add gateway API to scheme")
+	require.NoError(t, corev1.AddToScheme(scheme), "should add core API to scheme")
 	cfg := &meta.GatewayCtrlConfig{
 		Communities: map[uint32]string{
 			0: "50000:1000",
@@ -273,3 +866,157 @@ func TestGatewayValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestGatewayDefaultBond(t *testing.T) {
+	gw := gwa("gw-1", func(gw *v1alpha1.Gateway) {
+		gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{
+			"bond0": {
+				IPs:  []string{"172.30.128.3/31"},
+				MTU:  9000,
+				Bond: &v1alpha1.GatewayInterfaceBond{Members: []string{"port1", "port0"}},
+			},
+			"port0": {Kernel: "eth0"},
+			"port1": {Kernel: "eth1", MTU: 9000},
+		}
+	})
+	gw.Default()
+
+	bond := gw.Spec.Interfaces["bond0"].Bond
+	require.NotNil(t, bond)
+	assert.Equal(t, []string{"port0", "port1"}, bond.Members, "members should be sorted")
+	assert.Equal(t, v1alpha1.GatewayBondLACPModeActive, bond.LACPMode)
+	assert.Equal(t, v1alpha1.GatewayBondLACPRateSlow, bond.LACPRate)
+	assert.Equal(t, v1alpha1.GatewayBondHashPolicyLayer34, bond.HashPolicy)
+	assert.Equal(t, uint32(9000), gw.Spec.Interfaces["port0"].MTU, "member should inherit bond MTU")
+	assert.Equal(t, map[string]string{"port0": "bond0", "port1": "bond0"}, gw.Spec.BondMembers())
+}
+
+func TestGatewayDefaultResources(t *testing.T) {
+	gw := gwa("gw-1", func(gw *v1alpha1.Gateway) {
+		gw.Spec.Resources.Hugepages = 512
+	})
+	gw.Default()
+
+	assert.Equal(t, v1alpha1.GatewayHugepageSize2Mi, gw.Spec.Resources.HugepageSize)
+	assert.Equal(t, v1alpha1.DefaultDataplaneMemory, gw.Spec.Resources.Memory)
+}
+
+func TestGatewayValidateUpdate(t *testing.T) {
+	confirmed := func(gw *v1alpha1.Gateway) {
+		gw.Annotations = map[string]string{v1alpha1.GatewayConfirmAnnotation: "true"}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		old      *v1alpha1.Gateway
+		new      *v1alpha1.Gateway
+		err      bool
+		warnings int
+	}{
+		{
+			name: "no-changes",
+			old:  gwa("gw-1"),
+			new:  gwa("gw-1"),
+		},
+		{
+			name: "non-canonical-old",
+			old:  gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.ProtocolIPv6 = "2001:DB8::1/128" }),
+			new:  gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.ProtocolIPv6 = "2001:db8::1/128" }),
+		},
+		{
+			name: "vtep-ip-not-confirmed",
+			old:  gwa("gw-1"),
+			new:  gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.VTEPIP = "172.30.12.5/32" }),
+			err:  true,
+		},
+		{
+			name:     "vtep-ip-confirmed",
+			old:      gwa("gw-1"),
+			new:      gwa("gw-1", confirmed, func(gw *v1alpha1.Gateway) { gw.Spec.VTEPIP = "172.30.12.5/32" }),
+			warnings: 1,
+		},
+		{
+			name: "vtep-ip-confirmed-by-previous-update",
+			old:  gwa("gw-1", confirmed, func(gw *v1alpha1.Gateway) { gw.Spec.ASN = 65102 }),
+			new: gwa("gw-1", confirmed, func(gw *v1alpha1.Gateway) {
+				gw.Spec.ASN = 65102
+				gw.Spec.VTEPIP = "172.30.12.5/32"
+			}),
+			err: true,
+		},
+		{
+			name: "non-disruptive-with-previous-confirmation",
+			old:  gwa("gw-1", confirmed, func(gw *v1alpha1.Gateway) { gw.Spec.ASN = 65102 }),
+			new: gwa("gw-1", confirmed, func(gw *v1alpha1.Gateway) {
+				gw.Spec.ASN = 65102
+				gw.Spec.Neighbors[0].ASN = 65200
+			}),
+			warnings: 1,
+		},
+		{
+			name: "asn-not-confirmed",
+			old:  gwa("gw-1"),
+			new:  gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.ASN = 65102 }),
+			err:  true,
+		},
+		{
+			name: "interface-removed-not-confirmed",
+			old:  gwa("gw-1"),
+			new:  gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.Interfaces = map[string]v1alpha1.GatewayInterface{} }),
+			err:  true,
+		},
+		{
+			name: "driver-changed",
+			old:  gwa("gw-1"),
+			new: gwa("gw-1", confirmed, func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0"] = v1alpha1.GatewayInterface{PCI: "0000:00:01.0", IPs: []string{"172.30.128.3/31"}, MTU: 1500}
+			}),
+			err: true,
+		},
+		{
+			name: "interface-type-changed",
+			old:  gwa("gw-1"),
+			new: gwa("gw-1", confirmed, func(gw *v1alpha1.Gateway) {
+				gw.Spec.Interfaces["port0"] = v1alpha1.GatewayInterface{
+					IPs:  []string{"172.30.128.3/31"},
+					MTU:  1500,
+					VLAN: &v1alpha1.GatewayInterfaceVLAN{Parent: "port1", ID: 10},
+				}
+			}),
+			err: true,
+		},
+		{
+			name:     "neighbor-and-mtu-changed",
+			old:      gwa("gw-1"),
+			new:      gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.Neighbors[0].ASN = 65200 }, withMTU("port0", 9000)),
+			warnings: 2,
+		},
+		{
+			name:     "node-changed",
+			old:      gwa("gw-1"),
+			new:      gwa("gw-1", func(gw *v1alpha1.Gateway) { gw.Spec.NodeName = "node-2" }),
+			warnings: 1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.new.Default()
+
+			warnings, err := tt.new.ValidateUpdate(tt.old)
+			if tt.err {
+				require.ErrorIs(t, err, v1alpha1.ErrInvalidGW)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, warnings, tt.warnings, "warnings: %v", warnings)
+		})
+	}
+}
+
+func withMTU(iface string, mtu uint32) func(gw *v1alpha1.Gateway) {
+	return func(gw *v1alpha1.Gateway) {
+		i := gw.Spec.Interfaces[iface]
+		i.MTU = mtu
+		gw.Spec.Interfaces[iface] = i
+	}
+}
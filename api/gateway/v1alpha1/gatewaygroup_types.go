@@ -5,32 +5,145 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	DefaultGatewayGroup = "default"
+	// AutoGatewayGroup is used in the peering instead of the group name to let the controller pick the group
+	AutoGatewayGroup = "auto"
+
+	DefaultGatewayGroupHoldDown = 10 * time.Second
+	MaxGatewayGroupHoldDown     = 1 * time.Hour
+)
+
+// +kubebuilder:validation:Enum=active-standby;ecmp
+// GatewayGroupMode defines how the group members share the traffic
+type GatewayGroupMode string
+
+const (
+	// GatewayGroupModeActiveStandby sends all traffic to the member with the lowest priority value, the rest are
+	// standby and take over in the order of priority
+	GatewayGroupModeActiveStandby GatewayGroupMode = "active-standby"
+	// GatewayGroupModeECMP spreads traffic between all members proportionally to their weights
+	GatewayGroupModeECMP GatewayGroupMode = "ecmp"
 )
 
+var GatewayGroupModes = []GatewayGroupMode{
+	GatewayGroupModeActiveStandby,
+	GatewayGroupModeECMP,
+}
+
+var ErrInvalidGwGroup = errors.New("invalid gateway group")
+
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // GatewayGroupSpec defines the desired state of GatewayGroup
-type GatewayGroupSpec struct{}
+type GatewayGroupSpec struct {
+	// Images overrides the images from the controller config for all gateways in the group
+	Images GatewayImages `json:"images,omitempty"`
+	// Canary is the name of the gateway in the group to get the group images first, the rest of the gateways in the
+	// group are only updated after the canary runs the group images and is ready
+	Canary string `json:"canary,omitempty"`
+	// Redundancy defines how the group members share the traffic and fail over
+	Redundancy GatewayGroupRedundancy `json:"redundancy,omitempty"`
+	// MinHealthyMembers is the number of healthy members below which the group is considered degraded, all members
+	// are expected to be healthy if not set
+	MinHealthyMembers uint8 `json:"minHealthyMembers,omitempty"`
+	// Placement configures how the group is used for the peerings with the auto gateway group
+	Placement GatewayGroupPlacement `json:"placement,omitempty"`
+}
+
+// GatewayGroupPlacement configures the automatic placement of peerings to the group
+type GatewayGroupPlacement struct {
+	// Exclude makes the group only get peerings explicitly assigned to it
+	Exclude bool `json:"exclude,omitempty"`
+	// MaxPeerings is the number of peerings assigned to the group after which no more peerings are placed to it
+	// automatically, explicitly assigned peerings are counted but never rejected, unlimited if not set
+	MaxPeerings uint32 `json:"maxPeerings,omitempty"`
+}
+
+// GatewayGroupRedundancy defines how the group members share the traffic and fail over
+type GatewayGroupRedundancy struct {
+	// Mode is how the traffic is shared between members: active-standby by priority (default) or ecmp by weights
+	Mode GatewayGroupMode `json:"mode,omitempty"`
+	// Preempt makes the member with the better priority take the traffic back once it returns, it's only used in
+	// active-standby mode and defaults to true, it's cleared when switching to other modes
+	Preempt *bool `json:"preempt,omitempty"`
+	// HoldDown is how long a returning member should be up before it gets traffic again to avoid flapping, 10s if
+	// not set or set to 0 (it can't be disabled), up to 1h
+	HoldDown kmetav1.Duration `json:"holdDown,omitempty"`
+}
 
 // GatewayGroupStatus defines the observed state of GatewayGroup.
 type GatewayGroupStatus struct {
+	// Members of the group sorted the same way as for the traffic distribution: by priority and name
+	Members []GatewayGroupMemberStatus `json:"members,omitempty"`
+	// Healthy is the number of healthy members
+	Healthy int `json:"healthy,omitempty"`
+	// Active is the list of members expected to carry the traffic: the preferred healthy member in active-standby
+	// mode or all healthy members in ecmp mode
+	Active []string `json:"active,omitempty"`
+	// Peerings is the number of peerings assigned to the group
+	Peerings int `json:"peerings,omitempty"`
+	// Communities maps gateways to the IDs of the communities allocated to them from the controller config, an
+	// allocation is kept after the gateway leaves the group until the community is needed for another member
+	Communities map[string]uint32 `json:"communities,omitempty"`
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
 	// +listMapKey=type
 	// +optional
-	// Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Conditions []kmetav1.Condition `json:"conditions,omitempty"`
+}
+
+// GatewayGroupMemberStatus is the observed state of a single group member
+type GatewayGroupMemberStatus struct {
+	Name     string `json:"name"`
+	Priority uint32 `json:"priority,omitempty"`
+	Weight   uint8  `json:"weight,omitempty"`
+	// Alive is true if the member agent heartbeat is fresh
+	Alive bool `json:"alive,omitempty"`
+	// Applied is true if the member agent applied the latest generation of its config
+	Applied bool `json:"applied,omitempty"`
+	// Drained is true if the member is drained and doesn't get any traffic
+	Drained bool `json:"drained,omitempty"`
+}
+
+// IsHealthy returns true if the member is alive and up to date
+func (m *GatewayGroupMemberStatus) IsHealthy() bool {
+	return m.Alive && m.Applied
 }
 
+const (
+	// GatewayGroupConditionDegraded is true when the group has fewer healthy members than required
+	GatewayGroupConditionDegraded = "Degraded"
+)
+
+const (
+	GatewayGroupReasonHealthy   = "MembersHealthy"
+	GatewayGroupReasonUnhealthy = "MembersUnhealthy"
+	GatewayGroupReasonNoMembers = "NoMembers"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=gwgr
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.redundancy.mode`,priority=0
+// +kubebuilder:printcolumn:name="Healthy",type=integer,JSONPath=`.status.healthy`,priority=0
+// +kubebuilder:printcolumn:name="Active",type=string,JSONPath=`.status.active`,priority=0
+// +kubebuilder:printcolumn:name="Peerings",type=integer,JSONPath=`.status.peerings`,priority=0
+// +kubebuilder:printcolumn:name="Degraded",type=string,JSONPath=`.status.conditions[?(@.type=="Degraded")].status`,priority=0
+// +kubebuilder:printcolumn:name="Canary",type=string,JSONPath=`.spec.canary`,priority=0
+// +kubebuilder:printcolumn:name="DPImage",type=string,JSONPath=`.spec.images.dataplane`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
 // GatewayGroup is the Schema for the gatewaygroups API
 type GatewayGroup struct {
 	kmetav1.TypeMeta   `json:",inline"`
@@ -56,8 +169,111 @@ func init() {
 }
 
 func (gg *GatewayGroup) Default() {
+	gg.Spec.Redundancy.Default()
+}
+
+func (r *GatewayGroupRedundancy) Default() {
+	if r.Mode == "" {
+		r.Mode = GatewayGroupModeActiveStandby
+	}
+	if r.Mode == GatewayGroupModeActiveStandby && r.Preempt == nil {
+		r.Preempt = ptr.To(true)
+	}
+	// preempt could be defaulted while in active-standby mode so it shouldn't block switching to other modes
+	if r.Mode != GatewayGroupModeActiveStandby {
+		r.Preempt = nil
+	}
+	if r.HoldDown.Duration == 0 {
+		r.HoldDown = kmetav1.Duration{Duration: DefaultGatewayGroupHoldDown}
+	}
+}
+
+func (r *GatewayGroupRedundancy) validate() error {
+	if !slices.Contains(GatewayGroupModes, r.Mode) {
+		return fmt.Errorf("redundancy mode %q must be one of %v: %w", r.Mode, GatewayGroupModes, ErrInvalidGwGroup)
+	}
+	if r.Mode != GatewayGroupModeActiveStandby && r.Preempt != nil {
+		return fmt.Errorf("preempt is only supported in %s mode: %w", GatewayGroupModeActiveStandby, ErrInvalidGwGroup)
+	}
+	if r.HoldDown.Duration < 0 || r.HoldDown.Duration > MaxGatewayGroupHoldDown {
+		return fmt.Errorf("hold down %s must not be negative or longer than %s: %w", r.HoldDown.Duration, MaxGatewayGroupHoldDown, ErrInvalidGwGroup)
+	}
+
+	return nil
 }
 
-func (gg *GatewayGroup) Validate(_ context.Context, _ kclient.Reader) error {
+func (gg *GatewayGroup) Validate(ctx context.Context, kube kclient.Reader) error {
+	if gg.Name == AutoGatewayGroup {
+		return fmt.Errorf("name %q is reserved for the automatic peering placement: %w", AutoGatewayGroup, ErrInvalidGwGroup)
+	}
+
+	if err := gg.Spec.Images.validate(); err != nil {
+		return errors.Join(err, ErrInvalidGwGroup)
+	}
+
+	if err := gg.Spec.Redundancy.validate(); err != nil {
+		return err
+	}
+
+	if gg.Spec.Redundancy.Mode != GatewayGroupModeECMP && kube != nil {
+		gws := &GatewayList{}
+		if err := kube.List(ctx, gws, kclient.InNamespace(gg.Namespace)); err != nil {
+			return fmt.Errorf("listing gateways: %w", err)
+		}
+		for _, gw := range gws.Items {
+			if slices.ContainsFunc(gw.Spec.Groups, func(m GatewayGroupMembership) bool { return m.Name == gg.Name && m.Weight > 0 }) {
+				return fmt.Errorf("gateway %s has weight set which is only supported in %s mode: %w", gw.Name, GatewayGroupModeECMP, ErrInvalidGwGroup)
+			}
+		}
+	}
+
+	if gg.Spec.Canary != "" {
+		if gg.Spec.Images.IsEmpty() {
+			return fmt.Errorf("canary requires group images to be set: %w", ErrInvalidGwGroup)
+		}
+
+		if kube != nil {
+			canary := &Gateway{}
+			if err := kube.Get(ctx, kclient.ObjectKey{Namespace: gg.Namespace, Name: gg.Spec.Canary}, canary); err != nil {
+				if kapierrors.IsNotFound(err) {
+					return fmt.Errorf("canary gateway %s not found: %w", gg.Spec.Canary, ErrInvalidGwGroup)
+				}
+
+				return fmt.Errorf("getting canary gateway %s: %w", gg.Spec.Canary, err)
+			}
+			if !slices.ContainsFunc(canary.Spec.Groups, func(m GatewayGroupMembership) bool { return m.Name == gg.Name }) {
+				return fmt.Errorf("canary gateway %s is not a member of the group: %w", gg.Spec.Canary, ErrInvalidGwGroup)
+			}
+		}
+	}
+
 	return nil
 }
+
+// ValidateDelete rejects deleting the gateway group while gateways or peerings still reference it
+func (gg *GatewayGroup) ValidateDelete(ctx context.Context, kube kclient.Reader) ([]string, error) {
+	dependents := map[string][]string{}
+
+	gws := &GatewayList{}
+	if err := kube.List(ctx, gws, kclient.InNamespace(gg.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing gateways: %w", err)
+	}
+	for _, gw := range gws.Items {
+		if slices.ContainsFunc(gw.Spec.Groups, func(m GatewayGroupMembership) bool { return m.Name == gg.Name }) {
+			dependents["gateways"] = append(dependents["gateways"], gw.Name)
+		}
+	}
+
+	peerings := &PeeringList{}
+	if err := kube.List(ctx, peerings, kclient.InNamespace(gg.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing peerings: %w", err)
+	}
+	for _, peering := range peerings.Items {
+		// auto placed peerings depend on the group they're placed to
+		if peering.AssignedGatewayGroup() == gg.Name {
+			dependents["peerings"] = append(dependents["peerings"], peering.Name)
+		}
+	}
+
+	return checkDependents(gg, "gateway group "+gg.Name, dependents)
+}
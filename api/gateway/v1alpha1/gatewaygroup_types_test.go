@@ -0,0 +1,108 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGatewayGroupDefaultRedundancy(t *testing.T) {
+	gwGr := &v1alpha1.GatewayGroup{}
+	gwGr.Default()
+	require.Equal(t, v1alpha1.GatewayGroupRedundancy{
+		Mode:     v1alpha1.GatewayGroupModeActiveStandby,
+		Preempt:  ptr.To(true),
+		HoldDown: kmetav1.Duration{Duration: v1alpha1.DefaultGatewayGroupHoldDown},
+	}, gwGr.Spec.Redundancy)
+
+	gwGr = &v1alpha1.GatewayGroup{Spec: v1alpha1.GatewayGroupSpec{Redundancy: v1alpha1.GatewayGroupRedundancy{
+		Mode:    v1alpha1.GatewayGroupModeActiveStandby,
+		Preempt: ptr.To(false),
+	}}}
+	gwGr.Default()
+	require.Equal(t, ptr.To(false), gwGr.Spec.Redundancy.Preempt, "explicit preempt should be kept")
+
+	// defaulted active-standby group switched to ecmp
+	gwGr = &v1alpha1.GatewayGroup{}
+	gwGr.Default()
+	gwGr.Spec.Redundancy.Mode = v1alpha1.GatewayGroupModeECMP
+	gwGr.Default()
+	require.Nil(t, gwGr.Spec.Redundancy.Preempt, "preempt should be cleared outside of active-standby")
+	require.NoError(t, gwGr.Validate(t.Context(), nil))
+}
+
+func TestGatewayGroupValidate(t *testing.T) {
+	redundancy := func(r v1alpha1.GatewayGroupRedundancy) *v1alpha1.GatewayGroup {
+		return withName("gr1", &v1alpha1.GatewayGroup{Spec: v1alpha1.GatewayGroupSpec{Redundancy: r}})
+	}
+	weighted := withName("gw-1", &v1alpha1.Gateway{Spec: v1alpha1.GatewaySpec{
+		Groups: []v1alpha1.GatewayGroupMembership{{Name: "gr1", Weight: 2}},
+	}})
+
+	for _, tt := range []struct {
+		name  string
+		gwGr  *v1alpha1.GatewayGroup
+		objs  []kclient.Object
+		error bool
+	}{
+		{
+			name: "default",
+			gwGr: withName("gr1", &v1alpha1.GatewayGroup{}),
+		},
+		{
+			name: "ecmp",
+			gwGr: redundancy(v1alpha1.GatewayGroupRedundancy{Mode: v1alpha1.GatewayGroupModeECMP}),
+			objs: []kclient.Object{weighted},
+		},
+		{
+			// cleared by defaulting
+			name: "ecmp-preempt",
+			gwGr: redundancy(v1alpha1.GatewayGroupRedundancy{Mode: v1alpha1.GatewayGroupModeECMP, Preempt: ptr.To(true)}),
+		},
+		{
+			name:  "invalid-mode",
+			gwGr:  redundancy(v1alpha1.GatewayGroupRedundancy{Mode: "round-robin"}),
+			error: true,
+		},
+		{
+			name:  "hold-down-too-long",
+			gwGr:  redundancy(v1alpha1.GatewayGroupRedundancy{HoldDown: kmetav1.Duration{Duration: 2 * time.Hour}}),
+			error: true,
+		},
+		{
+			name:  "reserved-name",
+			gwGr:  withName(v1alpha1.AutoGatewayGroup, &v1alpha1.GatewayGroup{}),
+			error: true,
+		},
+		{
+			name:  "active-standby-with-weighted-member",
+			gwGr:  withName("gr1", &v1alpha1.GatewayGroup{}),
+			objs:  []kclient.Object{weighted},
+			error: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objs...).Build()
+
+			tt.gwGr.Default()
+			err := tt.gwGr.Validate(t.Context(), kube)
+			if tt.error {
+				require.ErrorIs(t, err, v1alpha1.ErrInvalidGwGroup)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
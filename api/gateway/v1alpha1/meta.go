@@ -3,11 +3,57 @@
 
 package v1alpha1
 
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 var (
 	LabelPrefix    = "gateway.githedgehog.com/"
 	ListLabelValue = "true"
+
+	// ForceDeleteAnnotation set to "true" on an object allows deleting it while it's still used by other objects
+	ForceDeleteAnnotation = LabelPrefix + "force-delete"
 )
 
+var ErrInUse = errors.New("object is in use")
+
+// maxDependents is the max number of dependents of each kind listed in the in use errors
+const maxDependents = 10
+
+func isForceDelete(obj kmetav1.Object) bool {
+	return obj.GetAnnotations()[ForceDeleteAnnotation] == "true"
+}
+
+// checkDependents returns an error listing the dependents (kind to names) of the object being deleted unless it's
+// forced using the annotation in which case a warning is returned instead
+func checkDependents(obj kmetav1.Object, subject string, dependents map[string][]string) ([]string, error) {
+	if len(dependents) == 0 {
+		return nil, nil
+	}
+
+	parts := []string{}
+	for _, depKind := range slices.Sorted(maps.Keys(dependents)) {
+		names := slices.Sorted(slices.Values(dependents[depKind]))
+		if len(names) > maxDependents {
+			names = append(names[:maxDependents], fmt.Sprintf("and %d more", len(names)-maxDependents))
+		}
+		parts = append(parts, depKind+" "+strings.Join(names, ", "))
+	}
+	msg := fmt.Sprintf("%s is still used by %s", subject, strings.Join(parts, "; "))
+
+	if isForceDelete(obj) {
+		return []string{"force deleting, " + msg}, nil
+	}
+
+	return nil, fmt.Errorf("%s, set annotation %s=true to delete it anyway: %w", msg, ForceDeleteAnnotation, ErrInUse)
+}
+
 func ListLabelPrefix(listType string) string {
 	return listType + "." + LabelPrefix
 }
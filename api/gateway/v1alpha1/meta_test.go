@@ -0,0 +1,98 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateDelete(t *testing.T) {
+	peering := func(name, group string, vpcs ...string) *v1alpha1.Peering {
+		p := withName(name, &v1alpha1.Peering{Spec: v1alpha1.PeeringSpec{GatewayGroup: group, Peering: map[string]*v1alpha1.PeeringEntry{}}})
+		for _, vpc := range vpcs {
+			p.Spec.Peering[vpc] = &v1alpha1.PeeringEntry{}
+		}
+		p.Default()
+
+		return p
+	}
+	inGroup := func(group string) func(gw *v1alpha1.Gateway) {
+		return func(gw *v1alpha1.Gateway) {
+			gw.Spec.Groups = []v1alpha1.GatewayGroupMembership{{Name: group}}
+		}
+	}
+	placed := func(p *v1alpha1.Peering, group string) *v1alpha1.Peering {
+		p.Status.GatewayGroup = group
+
+		return p
+	}
+	forced := func(obj kclient.Object) kclient.Object {
+		obj.SetAnnotations(map[string]string{v1alpha1.ForceDeleteAnnotation: "true"})
+
+		return obj
+	}
+
+	objs := []kclient.Object{
+		withName("gr1", &v1alpha1.GatewayGroup{}),
+		withName("gr2", &v1alpha1.GatewayGroup{}),
+		withName("gr3", &v1alpha1.GatewayGroup{}),
+		withName("gr4", &v1alpha1.GatewayGroup{}),
+		withName("gr5", &v1alpha1.GatewayGroup{}),
+		gwa("gw-1", inGroup("gr1")),
+		gwa("gw-2", inGroup("gr2")),
+		gwa("gw-3", inGroup("gr2")),
+		gwa("gw-4", inGroup("gr4")),
+		withName("vpc1", &v1alpha1.VPCInfo{}),
+		withName("vpc3", &v1alpha1.VPCInfo{}),
+		peering("vpc1--vpc2", "gr1", "vpc1", "vpc2"),
+		peering("vpc2--vpc4", "gr2", "vpc2", "vpc4"),
+		placed(peering("vpc5--vpc6", v1alpha1.AutoGatewayGroup, "vpc5", "vpc6"), "gr4"),
+		placed(peering("vpc6--vpc7", v1alpha1.AutoGatewayGroup, "vpc6", "vpc7"), "gr5"),
+	}
+
+	type deletable interface {
+		kclient.Object
+		ValidateDelete(ctx context.Context, kube kclient.Reader) ([]string, error)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		obj      deletable
+		err      bool
+		warnings int
+	}{
+		{name: "gw-last-member-with-peerings", obj: gwa("gw-1", inGroup("gr1")), err: true},
+		{name: "gw-last-member-forced", obj: forced(gwa("gw-1", inGroup("gr1"))).(deletable), warnings: 1},
+		{name: "gw-not-last-member", obj: gwa("gw-2", inGroup("gr2"))},
+		{name: "gw-last-member-with-auto-placed-peerings", obj: gwa("gw-4", inGroup("gr4")), err: true},
+		{name: "group-with-gateways-and-peerings", obj: withName("gr1", &v1alpha1.GatewayGroup{}), err: true},
+		{name: "group-with-auto-placed-peerings", obj: withName("gr5", &v1alpha1.GatewayGroup{}), err: true},
+		{name: "group-unused", obj: withName("gr3", &v1alpha1.GatewayGroup{})},
+		{name: "vpc-with-peerings", obj: withName("vpc1", &v1alpha1.VPCInfo{}), err: true},
+		{name: "vpc-forced", obj: forced(withName("vpc1", &v1alpha1.VPCInfo{})).(deletable), warnings: 1},
+		{name: "vpc-unused", obj: withName("vpc3", &v1alpha1.VPCInfo{})},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+			warnings, err := tt.obj.ValidateDelete(t.Context(), kube)
+			if tt.err {
+				require.ErrorIs(t, err, v1alpha1.ErrInUse)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, warnings, tt.warnings)
+		})
+	}
+}
@@ -27,7 +27,8 @@ const (
 
 // PeeringSpec defines the desired state of Peering.
 type PeeringSpec struct {
-	// GatewayGroup is the name of the gateway group that should process the peering
+	// GatewayGroup is the name of the gateway group that should process the peering or "auto" to let the controller
+	// pick the group based on the group capacity and load
 	GatewayGroup string `json:"gatewayGroup,omitempty"`
 	// Peerings is a map of peering entries for each VPC participating in the peering (keyed by VPC name)
 	Peering map[string]*PeeringEntry `json:"peering,omitempty"`
@@ -104,12 +105,97 @@ type PeeringEntryAs struct {
 }
 
 // PeeringStatus defines the observed state of Peering.
-type PeeringStatus struct{}
+type PeeringStatus struct {
+	// GatewayGroup is the gateway group processing the peering, it's chosen by the controller for the auto gateway
+	// group and kept until the rebalance is requested using the annotation
+	GatewayGroup string `json:"gatewayGroup,omitempty"`
+	// Traffic is the traffic on the peering summed across the gateways of the group keyed by the direction as
+	// VPC1->VPC2, it's collected per pair of VPCs by the gateways
+	Traffic map[string]PeeringTraffic `json:"traffic,omitempty"`
+	// Gateways is the traffic on the peering per gateway of the group keyed by the gateway name and then by direction
+	Gateways map[string]map[string]PeeringTraffic `json:"gateways,omitempty"`
+	// Exposed is the effective expose config keyed by the VPC name after resolving the vpcSubnet and not entries
+	Exposed map[string][]PeeringExposeStatus `json:"exposed,omitempty"`
+	// Propagation is the gateway agent config generation the peering is expected to be applied at (or withdrawn at
+	// on deletion) keyed by the gateway name
+	Propagation map[string]PeeringPropagation `json:"propagation,omitempty"`
+	// The status of each condition is one of True, False, or Unknown.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []kmetav1.Condition `json:"conditions,omitempty"`
+}
+
+// PeeringPropagation tracks the peering in the config of a single gateway agent
+type PeeringPropagation struct {
+	// Generation is the peering generation included in the gateway agent config
+	Generation int64 `json:"generation,omitempty"`
+	// AgentGeneration is the first observed gateway agent generation including the peering generation or the first
+	// one without the peering if it's withdrawn
+	AgentGeneration int64 `json:"agentGeneration,omitempty"`
+	// Withdrawn is true if the peering is removed from the gateway agent config on the peering deletion
+	Withdrawn bool `json:"withdrawn,omitempty"`
+}
+
+const (
+	// PeeringConditionReady is true when the peering is applied on all gateways of its group
+	PeeringConditionReady = "Ready"
+	// PeeringConditionRemoved is true when the peering being deleted is withdrawn from all gateways of its group
+	PeeringConditionRemoved = "Removed"
+)
+
+const (
+	PeeringReasonApplied     = "Applied"
+	PeeringReasonPending     = "Pending"
+	PeeringReasonNotPlaced   = "NotPlaced"
+	PeeringReasonVPCNotFound = "VPCNotFound"
+	PeeringReasonNoGateways  = "NoGateways"
+	PeeringReasonWithdrawn   = "Withdrawn"
+)
+
+// PeeringTraffic is the traffic on the peering in one direction
+type PeeringTraffic struct {
+	// Packets is the number of packets sent
+	Packets uint64 `json:"packets,omitempty"`
+	// Bytes is the number of bytes sent
+	Bytes uint64 `json:"bytes,omitempty"`
+	// Drops is the number of packets dropped
+	Drops uint64 `json:"drops,omitempty"`
+	// BytesPerSecond is the number of bytes sent per second
+	BytesPerSecond float64 `json:"bps,omitempty"`
+	// PktsPerSecond is the number of packets sent per second
+	PktsPerSecond float64 `json:"pps,omitempty"`
+}
+
+// PeeringExposeStatus is the effective config of a single expose of the VPC
+type PeeringExposeStatus struct {
+	// IPs is the VPC prefixes exposed to the peer VPC
+	IPs []string `json:"ips,omitempty"`
+	// As is the NAT range the exposed IPs are translated to and advertised to the peer VPC as instead of the IPs
+	As []string `json:"as,omitempty"`
+	// NAT is the NAT mode used for the expose: static, masquerade or portForward
+	NAT string `json:"nat,omitempty"`
+	// Default is true if the expose is the default destination
+	Default bool `json:"default,omitempty"`
+}
+
+const (
+	PeeringExposeNATStatic      = "static"
+	PeeringExposeNATMasquerade  = "masquerade"
+	PeeringExposeNATPortForward = "portForward"
+)
+
+// PeeringRebalanceAnnotation set to "true" on a peering with the auto gateway group makes the controller pick the
+// group again, the annotation is removed once it's done
+var PeeringRebalanceAnnotation = LabelPrefix + "rebalance"
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=hedgehog;hedgehog-gateway,shortName=peer
 // +kubebuilder:printcolumn:name="GatewayGroup",type=string,JSONPath=`.spec.gatewayGroup`,priority=0
+// +kubebuilder:printcolumn:name="Placed",type=string,JSONPath=`.status.gatewayGroup`,priority=1
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,priority=0
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,priority=0
 // Peering is the Schema for the peerings API.
 type Peering struct {
@@ -149,6 +235,15 @@ func (p *Peering) Default() {
 	for _, peering := range p.Spec.Peering {
 		for idx := range peering.Expose {
 			expose := &peering.Expose[idx]
+			for ipIdx := range expose.IPs {
+				expose.IPs[ipIdx].CIDR = canonicalPrefix(expose.IPs[ipIdx].CIDR)
+				expose.IPs[ipIdx].Not = canonicalPrefix(expose.IPs[ipIdx].Not)
+			}
+			for asIdx := range expose.As {
+				expose.As[asIdx].CIDR = canonicalPrefix(expose.As[asIdx].CIDR)
+				expose.As[asIdx].Not = canonicalPrefix(expose.As[asIdx].Not)
+			}
+
 			nat := expose.NAT
 			if nat != nil {
 				if nat.Masquerade != nil {
@@ -171,6 +266,19 @@ func (p *Peering) Default() {
 	}
 }
 
+// AssignedGatewayGroup returns the group processing the peering, it's empty if the peering with the auto gateway
+// group isn't placed yet
+func (p *Peering) AssignedGatewayGroup() string {
+	switch p.Spec.GatewayGroup {
+	case "":
+		return DefaultGatewayGroup
+	case AutoGatewayGroup:
+		return p.Status.GatewayGroup
+	default:
+		return p.Spec.GatewayGroup
+	}
+}
+
 func (p *Peering) Validate(ctx context.Context, kube kclient.Reader) error {
 	if p.Spec.GatewayGroup == "" {
 		return fmt.Errorf("gateway group must be specified %s", p.Name) //nolint:err113
@@ -265,6 +373,15 @@ func (p *Peering) Validate(ctx context.Context, kube kclient.Reader) error {
 					return fmt.Errorf("exactly one of masquerade, static, or portForward must be set in NAT section for peering expose of VPC %s", name) //nolint:err113
 				}
 
+				ipsV4, ipsV6 := exposeIPFamilies(expose)
+				asV4, asV6 := exposeAsFamilies(expose)
+				if asV4 && asV6 {
+					return fmt.Errorf("as prefixes must be all IPv4 or all IPv6 in peering expose of VPC %s", name) //nolint:err113
+				}
+				if (ipsV4 || ipsV6) && (ipsV4 != asV4 || ipsV6 != asV6) {
+					return fmt.Errorf("translation between IPv4 and IPv6 (NAT64/NAT46) is not supported in peering expose of VPC %s", name) //nolint:err113
+				}
+
 				if expose.NAT.PortForward != nil {
 					if len(expose.NAT.PortForward.Ports) == 0 {
 						return fmt.Errorf("at least one port forwarding rule must be set in NAT section for peering expose of VPC %s", name) //nolint:err113
@@ -295,13 +412,16 @@ func (p *Peering) Validate(ctx context.Context, kube kclient.Reader) error {
 	}
 
 	if kube != nil {
-		gwGroup := &GatewayGroup{}
-		if err := kube.Get(ctx, kclient.ObjectKey{Name: p.Spec.GatewayGroup, Namespace: p.Namespace}, gwGroup); err != nil {
-			if kapierrors.IsNotFound(err) {
-				return fmt.Errorf("gateway group %s not found", p.Spec.GatewayGroup) //nolint:err113
-			}
+		// the group is picked by the controller later for the auto gateway group
+		if p.Spec.GatewayGroup != AutoGatewayGroup {
+			gwGroup := &GatewayGroup{}
+			if err := kube.Get(ctx, kclient.ObjectKey{Name: p.Spec.GatewayGroup, Namespace: p.Namespace}, gwGroup); err != nil {
+				if kapierrors.IsNotFound(err) {
+					return fmt.Errorf("gateway group %s not found", p.Spec.GatewayGroup) //nolint:err113
+				}
 
-			return fmt.Errorf("failed to get gateway group %s: %w", p.Spec.GatewayGroup, err)
+				return fmt.Errorf("failed to get gateway group %s: %w", p.Spec.GatewayGroup, err)
+			}
 		}
 		// check for overlaps of exposed IPs towards either of the VPCs in the peering we are validating
 		peeringVPCs := maps.Keys(p.Spec.Peering)
@@ -380,6 +500,45 @@ func collectExposedCIDRs(entry *PeeringEntry, cidrs []string) []string {
 	return cidrs
 }
 
+// exposeIPFamilies returns if there are IPv4 and IPv6 prefixes in the expose IPs, vpcSubnet entries are ignored as
+// they can't be resolved without VPCInfo
+func exposeIPFamilies(expose PeeringEntryExpose) (bool, bool) {
+	prefixes := []string{}
+	for _, ip := range expose.IPs {
+		prefixes = append(prefixes, ip.CIDR, ip.Not)
+	}
+
+	return prefixFamilies(prefixes)
+}
+
+// exposeAsFamilies returns if there are IPv4 and IPv6 prefixes in the expose As
+func exposeAsFamilies(expose PeeringEntryExpose) (bool, bool) {
+	prefixes := []string{}
+	for _, as := range expose.As {
+		prefixes = append(prefixes, as.CIDR, as.Not)
+	}
+
+	return prefixFamilies(prefixes)
+}
+
+func prefixFamilies(prefixes []string) (bool, bool) {
+	v4, v6 := false, false
+	for _, in := range prefixes {
+		prefix, err := netip.ParsePrefix(in)
+		if err != nil {
+			continue
+		}
+
+		if prefix.Addr().Is4() {
+			v4 = true
+		} else {
+			v6 = true
+		}
+	}
+
+	return v4, v6
+}
+
 func validatePort(in string) error {
 	if strings.TrimSpace(in) != in {
 		return fmt.Errorf("invalid port %q: should not contain leading or trailing whitespace", in) //nolint:err113
@@ -64,6 +64,25 @@ func TestPeeringWithVpcsNoNAT(t *testing.T) {
 	assert.Equal(t, ref, peering)
 }
 
+func TestPeeringAssignedGatewayGroup(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		spec     string
+		status   string
+		expected string
+	}{
+		{name: "empty", expected: DefaultGatewayGroup},
+		{name: "explicit", spec: "gr1", status: "gr2", expected: "gr1"},
+		{name: "auto-placed", spec: AutoGatewayGroup, status: "gr2", expected: "gr2"},
+		{name: "auto-not-placed", spec: AutoGatewayGroup},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peering := &Peering{Spec: PeeringSpec{GatewayGroup: tt.spec}, Status: PeeringStatus{GatewayGroup: tt.status}}
+			assert.Equal(t, tt.expected, peering.AssignedGatewayGroup())
+		})
+	}
+}
+
 func TestPeeringWithMultipleItemsInIPs(t *testing.T) {
 	common := &Peering{}
 	common.Spec.Peering = map[string]*PeeringEntry{
@@ -188,6 +207,43 @@ func TestPeeringWithStaticNAT(t *testing.T) {
 	assert.Equal(t, ref, peering)
 }
 
+func TestPeeringWithStaticNATMixedFamilies(t *testing.T) {
+	peering := &Peering{}
+	peering.Spec.Peering = map[string]*PeeringEntry{
+		"vpc1": {
+			Expose: []PeeringEntryExpose{
+				{
+					IPs: []PeeringEntryIP{
+						{CIDR: "FD00:1::/64"},
+					},
+					As: []PeeringEntryAs{
+						{CIDR: "192.168.1.0/24"},
+					},
+					NAT: &PeeringNAT{
+						Static: &PeeringNATStatic{},
+					},
+				},
+			},
+		},
+		"vpc2": {
+			Expose: []PeeringEntryExpose{
+				{
+					IPs: []PeeringEntryIP{
+						{CIDR: "fd00:2::/64"},
+					},
+				},
+			},
+		},
+	}
+
+	peering.Default()
+	assert.Equal(t, "fd00:1::/64", peering.Spec.Peering["vpc1"].Expose[0].IPs[0].CIDR, "CIDR should be canonicalized")
+	assert.Error(t, peering.Validate(t.Context(), nil), "NAT between IPv6 and IPv4 should not be allowed")
+
+	peering.Spec.Peering["vpc1"].Expose[0].As = []PeeringEntryAs{{CIDR: "fd00:ff::/64"}}
+	assert.NoError(t, peering.Validate(t.Context(), nil), "static NAT between IPv6 prefixes should be allowed")
+}
+
 func TestPeeringWithDoubleMasqueradeNAT(t *testing.T) {
 	common := &Peering{}
 	common.Spec.Peering = map[string]*PeeringEntry{
@@ -27,7 +27,7 @@ type VPCInfoSpec struct {
 }
 
 type VPCInfoSubnet struct {
-	// CIDR is the subnet CIDR block, such as "10.0.0.0/24"
+	// CIDR is the subnet CIDR block, such as "10.0.0.0/24" or "fd00:10::/64"
 	CIDR string `json:"cidr,omitempty"`
 }
 
@@ -68,7 +68,11 @@ func (vpc *VPCInfo) IsReady() bool {
 }
 
 func (vpc *VPCInfo) Default() {
-	// TODO add defaulting logic
+	for _, subnet := range vpc.Spec.Subnets {
+		if subnet != nil {
+			subnet.CIDR = canonicalPrefix(subnet.CIDR)
+		}
+	}
 }
 
 func (vpc *VPCInfo) Validate(_ context.Context, _ kclient.Reader) error {
@@ -77,10 +81,33 @@ func (vpc *VPCInfo) Validate(_ context.Context, _ kclient.Reader) error {
 	}
 
 	for name, subnet := range vpc.Spec.Subnets {
-		if _, err := netip.ParsePrefix(subnet.CIDR); err != nil {
+		if subnet == nil {
+			return fmt.Errorf("subnet %s must not be empty", name) //nolint:goerr113
+		}
+
+		prefix, err := netip.ParsePrefix(subnet.CIDR)
+		if err != nil {
 			return fmt.Errorf("invalid CIDR %s for subnet %s: %w", subnet.CIDR, name, err)
 		}
+		if prefix.Addr().Is4In6() {
+			return fmt.Errorf("CIDR %s for subnet %s must not be an IPv4-mapped IPv6 prefix", subnet.CIDR, name) //nolint:goerr113
+		}
 	}
 
 	return nil
 }
+
+// ValidateDelete rejects deleting the VPCInfo while peerings still use the VPC
+func (vpc *VPCInfo) ValidateDelete(ctx context.Context, kube kclient.Reader) ([]string, error) {
+	peerings := &PeeringList{}
+	if err := kube.List(ctx, peerings, kclient.InNamespace(vpc.Namespace), kclient.MatchingLabels{ListLabelVPC(vpc.Name): ListLabelValue}); err != nil {
+		return nil, fmt.Errorf("listing peerings: %w", err)
+	}
+
+	dependents := map[string][]string{}
+	for _, peering := range peerings.Items {
+		dependents["peerings"] = append(dependents["peerings"], peering.Name)
+	}
+
+	return checkDependents(vpc, "VPCInfo "+vpc.Name, dependents)
+}
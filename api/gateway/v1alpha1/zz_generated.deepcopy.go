@@ -8,6 +8,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -17,7 +18,7 @@ func (in *Gateway) DeepCopyInto(out *Gateway) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Gateway.
@@ -41,6 +42,26 @@ func (in *Gateway) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayBGPNeighbor) DeepCopyInto(out *GatewayBGPNeighbor) {
 	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(GatewayBGPNeighborAuth)
+		**out = **in
+	}
+	if in.Timers != nil {
+		in, out := &in.Timers, &out.Timers
+		*out = new(GatewayBGPNeighborTimers)
+		**out = **in
+	}
+	if in.BFD != nil {
+		in, out := &in.BFD, &out.BFD
+		*out = new(GatewayBGPNeighborBFD)
+		**out = **in
+	}
+	if in.AddressFamilies != nil {
+		in, out := &in.AddressFamilies, &out.AddressFamilies
+		*out = make([]GatewayBGPAddressFamily, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBGPNeighbor.
@@ -53,13 +74,109 @@ func (in *GatewayBGPNeighbor) DeepCopy() *GatewayBGPNeighbor {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBGPNeighborAuth) DeepCopyInto(out *GatewayBGPNeighborAuth) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBGPNeighborAuth.
+func (in *GatewayBGPNeighborAuth) DeepCopy() *GatewayBGPNeighborAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBGPNeighborAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBGPNeighborBFD) DeepCopyInto(out *GatewayBGPNeighborBFD) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBGPNeighborBFD.
+func (in *GatewayBGPNeighborBFD) DeepCopy() *GatewayBGPNeighborBFD {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBGPNeighborBFD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBGPNeighborTimers) DeepCopyInto(out *GatewayBGPNeighborTimers) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBGPNeighborTimers.
+func (in *GatewayBGPNeighborTimers) DeepCopy() *GatewayBGPNeighborTimers {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBGPNeighborTimers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBGPSummary) DeepCopyInto(out *GatewayBGPSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBGPSummary.
+func (in *GatewayBGPSummary) DeepCopy() *GatewayBGPSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBGPSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayDataplaneResources) DeepCopyInto(out *GatewayDataplaneResources) {
+	*out = *in
+	if in.NUMANode != nil {
+		in, out := &in.NUMANode, &out.NUMANode
+		*out = new(uint8)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayDataplaneResources.
+func (in *GatewayDataplaneResources) DeepCopy() *GatewayDataplaneResources {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayDataplaneResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayDrain) DeepCopyInto(out *GatewayDrain) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayDrain.
+func (in *GatewayDrain) DeepCopy() *GatewayDrain {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayDrain)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayGroup) DeepCopyInto(out *GatewayGroup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayGroup.
@@ -112,6 +229,21 @@ func (in *GatewayGroupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayGroupMemberStatus) DeepCopyInto(out *GatewayGroupMemberStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayGroupMemberStatus.
+func (in *GatewayGroupMemberStatus) DeepCopy() *GatewayGroupMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayGroupMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayGroupMembership) DeepCopyInto(out *GatewayGroupMembership) {
 	*out = *in
@@ -127,9 +259,48 @@ func (in *GatewayGroupMembership) DeepCopy() *GatewayGroupMembership {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayGroupPlacement) DeepCopyInto(out *GatewayGroupPlacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayGroupPlacement.
+func (in *GatewayGroupPlacement) DeepCopy() *GatewayGroupPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayGroupPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayGroupRedundancy) DeepCopyInto(out *GatewayGroupRedundancy) {
+	*out = *in
+	if in.Preempt != nil {
+		in, out := &in.Preempt, &out.Preempt
+		*out = new(bool)
+		**out = **in
+	}
+	out.HoldDown = in.HoldDown
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayGroupRedundancy.
+func (in *GatewayGroupRedundancy) DeepCopy() *GatewayGroupRedundancy {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayGroupRedundancy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayGroupSpec) DeepCopyInto(out *GatewayGroupSpec) {
 	*out = *in
+	out.Images = in.Images
+	in.Redundancy.DeepCopyInto(&out.Redundancy)
+	out.Placement = in.Placement
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayGroupSpec.
@@ -145,6 +316,30 @@ func (in *GatewayGroupSpec) DeepCopy() *GatewayGroupSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayGroupStatus) DeepCopyInto(out *GatewayGroupStatus) {
 	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]GatewayGroupMemberStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Communities != nil {
+		in, out := &in.Communities, &out.Communities
+		*out = make(map[string]uint32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayGroupStatus.
@@ -157,6 +352,21 @@ func (in *GatewayGroupStatus) DeepCopy() *GatewayGroupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayImages) DeepCopyInto(out *GatewayImages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayImages.
+func (in *GatewayImages) DeepCopy() *GatewayImages {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayInterface) DeepCopyInto(out *GatewayInterface) {
 	*out = *in
@@ -165,6 +375,16 @@ func (in *GatewayInterface) DeepCopyInto(out *GatewayInterface) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Bond != nil {
+		in, out := &in.Bond, &out.Bond
+		*out = new(GatewayInterfaceBond)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VLAN != nil {
+		in, out := &in.VLAN, &out.VLAN
+		*out = new(GatewayInterfaceVLAN)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayInterface.
@@ -177,6 +397,41 @@ func (in *GatewayInterface) DeepCopy() *GatewayInterface {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayInterfaceBond) DeepCopyInto(out *GatewayInterfaceBond) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayInterfaceBond.
+func (in *GatewayInterfaceBond) DeepCopy() *GatewayInterfaceBond {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayInterfaceBond)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayInterfaceVLAN) DeepCopyInto(out *GatewayInterfaceVLAN) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayInterfaceVLAN.
+func (in *GatewayInterfaceVLAN) DeepCopy() *GatewayInterfaceVLAN {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayInterfaceVLAN)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayList) DeepCopyInto(out *GatewayList) {
 	*out = *in
@@ -234,6 +489,18 @@ func (in *GatewayLogs) DeepCopy() *GatewayLogs {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayProfiling) DeepCopyInto(out *GatewayProfiling) {
 	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProfileTypes != nil {
+		in, out := &in.ProfileTypes, &out.ProfileTypes
+		*out = make([]GatewayProfileType, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayProfiling.
@@ -246,6 +513,21 @@ func (in *GatewayProfiling) DeepCopy() *GatewayProfiling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySecretKeyRef) DeepCopyInto(out *GatewaySecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySecretKeyRef.
+func (in *GatewaySecretKeyRef) DeepCopy() *GatewaySecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 	*out = *in
@@ -259,15 +541,27 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 	if in.Neighbors != nil {
 		in, out := &in.Neighbors, &out.Neighbors
 		*out = make([]GatewayBGPNeighbor, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.Logs.DeepCopyInto(&out.Logs)
-	out.Profiling = in.Profiling
+	in.Profiling.DeepCopyInto(&out.Profiling)
+	in.Resources.DeepCopyInto(&out.Resources)
 	if in.Groups != nil {
 		in, out := &in.Groups, &out.Groups
 		*out = make([]GatewayGroupMembership, len(*in))
 		copy(*out, *in)
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Images = in.Images
+	out.Drain = in.Drain
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
@@ -283,6 +577,17 @@ func (in *GatewaySpec) DeepCopy() *GatewaySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastHeartbeat.DeepCopyInto(&out.LastHeartbeat)
+	in.LastAppliedTime.DeepCopyInto(&out.LastAppliedTime)
+	out.BGP = in.BGP
+	out.Images = in.Images
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayStatus.
@@ -301,7 +606,7 @@ func (in *Peering) DeepCopyInto(out *Peering) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Peering.
@@ -404,6 +709,31 @@ func (in *PeeringEntryIP) DeepCopy() *PeeringEntryIP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringExposeStatus) DeepCopyInto(out *PeeringExposeStatus) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.As != nil {
+		in, out := &in.As, &out.As
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringExposeStatus.
+func (in *PeeringExposeStatus) DeepCopy() *PeeringExposeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringExposeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PeeringList) DeepCopyInto(out *PeeringList) {
 	*out = *in
@@ -533,6 +863,21 @@ func (in *PeeringNATStatic) DeepCopy() *PeeringNATStatic {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringPropagation) DeepCopyInto(out *PeeringPropagation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringPropagation.
+func (in *PeeringPropagation) DeepCopy() *PeeringPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PeeringSpec) DeepCopyInto(out *PeeringSpec) {
 	*out = *in
@@ -567,6 +912,63 @@ func (in *PeeringSpec) DeepCopy() *PeeringSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PeeringStatus) DeepCopyInto(out *PeeringStatus) {
 	*out = *in
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = make(map[string]PeeringTraffic, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make(map[string]map[string]PeeringTraffic, len(*in))
+		for key, val := range *in {
+			var outVal map[string]PeeringTraffic
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]PeeringTraffic, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Exposed != nil {
+		in, out := &in.Exposed, &out.Exposed
+		*out = make(map[string][]PeeringExposeStatus, len(*in))
+		for key, val := range *in {
+			var outVal []PeeringExposeStatus
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]PeeringExposeStatus, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Propagation != nil {
+		in, out := &in.Propagation, &out.Propagation
+		*out = make(map[string]PeeringPropagation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringStatus.
@@ -579,6 +981,21 @@ func (in *PeeringStatus) DeepCopy() *PeeringStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringTraffic) DeepCopyInto(out *PeeringTraffic) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringTraffic.
+func (in *PeeringTraffic) DeepCopy() *PeeringTraffic {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringTraffic)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPCInfo) DeepCopyInto(out *VPCInfo) {
 	*out = *in
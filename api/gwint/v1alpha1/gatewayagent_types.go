@@ -4,6 +4,8 @@
 package v1alpha1
 
 import (
+	"strings"
+
 	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -16,14 +18,19 @@ type VPCInfoData struct {
 }
 
 type GatewayGroupInfo struct {
-	// TODO inline gateway group config when it's added
-	Members []GatewayGroupMember `json:"members,omitempty"`
+	// Redundancy is the defaulted redundancy config of the group
+	Redundancy gwapi.GatewayGroupRedundancy `json:"redundancy,omitempty"`
+	Members    []GatewayGroupMember         `json:"members,omitempty"`
 }
 
 type GatewayGroupMember struct {
 	Name     string `json:"name"`
 	Priority uint32 `json:"priority"`
-	VTEPIP   string `json:"vtepIP"`
+	// Weight is the share of the group traffic for the member, it's only set in ecmp groups
+	Weight uint8  `json:"weight,omitempty"`
+	VTEPIP string `json:"vtepIP"`
+	// Community is the community allocated to the member, it's empty until the allocation is done
+	Community string `json:"community,omitempty"`
 }
 
 // GatewayAgentSpec defines the desired state of GatewayAgent.
@@ -39,8 +46,18 @@ type GatewayAgentSpec struct {
 }
 
 type GatewayAgentSpecConfig struct {
-	// FabricBFD defines if fabric-facing links should be configured with BFD
+	// FabricBFD defines if fabric-facing links should be configured with BFD, neighbor BFD config overrides it
 	FabricBFD bool `json:"fabricBFD,omitempty"`
+	// BGPAuthDir is the directory in the FRR container with BGP neighbor passwords, one file per neighbor named
+	// using BGPNeighborAuthKey
+	BGPAuthDir string `json:"bgpAuthDir,omitempty"`
+	// DrainCommunity is the community to attach to all advertisements if the gateway is drained in community mode
+	DrainCommunity string `json:"drainCommunity,omitempty"`
+}
+
+// BGPNeighborAuthKey returns the name of the key (file) with the password for the BGP neighbor
+func BGPNeighborAuthKey(neighborIP string) string {
+	return "neighbor-" + strings.ReplaceAll(neighborIP, ":", "-")
 }
 
 // GatewayAgentStatus defines the observed state of GatewayAgent.
@@ -292,6 +292,7 @@ func (in *GatewayAgentStatus) DeepCopy() *GatewayAgentStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayGroupInfo) DeepCopyInto(out *GatewayGroupInfo) {
 	*out = *in
+	in.Redundancy.DeepCopyInto(&out.Redundancy)
 	if in.Members != nil {
 		in, out := &in.Members, &out.Members
 		*out = make([]GatewayGroupMember, len(*in))
@@ -4,6 +4,14 @@
 package meta
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -18,6 +26,82 @@ type GatewayCtrlConfig struct {
 	FRRMetricsPort       uint16              `json:"frrMetricsPort,omitempty"`
 	Communities          map[uint32]string   `json:"communities,omitempty"`
 	FabricBFD            bool                `json:"fabricBFD,omitempty"`
+	// ProfilingURL is the default Pyroscope compatible endpoint for gateways with profiling enabled
+	ProfilingURL string `json:"profilingURL,omitempty"`
+	// DrainCommunity is attached to advertisements of the gateways drained in community mode
+	DrainCommunity string `json:"drainCommunity,omitempty"`
+}
+
+// Validate checks the controller config and returns all problems found at once
+func (cfg *GatewayCtrlConfig) Validate() error {
+	errs := []error{}
+
+	if cfg.Namespace == "" {
+		errs = append(errs, fmt.Errorf("namespace for the gateway pods must be set")) //nolint:err113
+	}
+	for name, ref := range map[string]string{"dataplaneRef": cfg.DataplaneRef, "frrRef": cfg.FRRRef, "toolboxRef": cfg.ToolboxRef} {
+		if ref == "" {
+			errs = append(errs, fmt.Errorf("%s image reference must be set", name)) //nolint:err113
+		}
+	}
+	if cfg.DataplaneMetricsPort == 0 {
+		errs = append(errs, fmt.Errorf("dataplaneMetricsPort must be non-zero")) //nolint:err113
+	}
+	if cfg.FRRMetricsPort == 0 {
+		errs = append(errs, fmt.Errorf("frrMetricsPort must be non-zero")) //nolint:err113
+	}
+	if cfg.DataplaneMetricsPort != 0 && cfg.DataplaneMetricsPort == cfg.FRRMetricsPort {
+		// both are listening on the host network of the gateway node
+		errs = append(errs, fmt.Errorf("dataplaneMetricsPort and frrMetricsPort must be different, both are %d", cfg.FRRMetricsPort)) //nolint:err113
+	}
+
+	seen := map[string]uint32{}
+	for _, id := range slices.Sorted(maps.Keys(cfg.Communities)) {
+		comm := cfg.Communities[id]
+		if err := ValidateCommunity(comm); err != nil {
+			errs = append(errs, fmt.Errorf("communities[%d]: %w", id, err))
+
+			continue
+		}
+		if other, exists := seen[comm]; exists {
+			errs = append(errs, fmt.Errorf("communities[%d]: community %s is already used for %d", id, comm, other)) //nolint:err113
+		}
+		seen[comm] = id
+	}
+	if cfg.DrainCommunity != "" {
+		if err := ValidateCommunity(cfg.DrainCommunity); err != nil {
+			errs = append(errs, fmt.Errorf("drainCommunity: %w", err))
+		}
+	}
+	if cfg.ProfilingURL != "" {
+		if err := ValidateProfilingURL(cfg.ProfilingURL); err != nil {
+			errs = append(errs, fmt.Errorf("profilingURL: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DefaultDrainCommunity is the well-known GRACEFUL_SHUTDOWN community (RFC 8326)
+const DefaultDrainCommunity = "65535:0"
+
+// DefaultProfilingURL is used if neither gateway nor controller config has profiling URL set
+const DefaultProfilingURL = "http://localhost:4040"
+
+// ValidateProfilingURL checks that the profiling endpoint is an absolute http(s) URL
+func ValidateProfilingURL(in string) error {
+	u, err := url.Parse(in)
+	if err != nil {
+		return fmt.Errorf("parsing URL %q: %w", in, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL %q must use http or https scheme", in) //nolint:err113
+	}
+	if u.Host == "" {
+		return fmt.Errorf("URL %q must have a host", in) //nolint:err113
+	}
+
+	return nil
 }
 
 type AgentConfig struct {
@@ -25,3 +109,19 @@ type AgentConfig struct {
 	Namespace        string `json:"namespace,omitempty"`
 	DataplaneAddress string `json:"dataplaneAddress,omitempty"`
 }
+
+// ValidateCommunity checks that the community is a standard BGP community in the asn:value format
+func ValidateCommunity(in string) error {
+	asn, value, ok := strings.Cut(in, ":")
+	if !ok {
+		return fmt.Errorf("community %q must be in asn:value format", in) //nolint:err113
+	}
+	if _, err := strconv.ParseUint(asn, 10, 16); err != nil {
+		return fmt.Errorf("parsing community %q ASN: %w", in, err)
+	}
+	if _, err := strconv.ParseUint(value, 10, 16); err != nil {
+		return fmt.Errorf("parsing community %q value: %w", in, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package meta_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/api/meta"
+)
+
+func TestGatewayCtrlConfigValidate(t *testing.T) {
+	valid := func(f ...func(cfg *meta.GatewayCtrlConfig)) *meta.GatewayCtrlConfig {
+		cfg := &meta.GatewayCtrlConfig{
+			Namespace:            "fab",
+			DataplaneRef:         "registry.local:31000/githedgehog/dataplane:v1",
+			FRRRef:               "registry.local:31000/githedgehog/dpdk-sys/frr:v1",
+			ToolboxRef:           "registry.local:31000/githedgehog/toolbox:v1",
+			DataplaneMetricsPort: 9442,
+			FRRMetricsPort:       9342,
+			Communities: map[uint32]string{
+				0: "50000:1000",
+				1: "50000:1001",
+			},
+		}
+		for _, fn := range f {
+			fn(cfg)
+		}
+
+		return cfg
+	}
+
+	for _, tt := range []struct {
+		name string
+		cfg  *meta.GatewayCtrlConfig
+		err  bool
+	}{
+		{name: "valid", cfg: valid()},
+		{name: "no-namespace", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.Namespace = "" }), err: true},
+		{name: "no-dataplane-ref", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.DataplaneRef = "" }), err: true},
+		{name: "no-metrics-port", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.FRRMetricsPort = 0 }), err: true},
+		{name: "same-metrics-ports", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.FRRMetricsPort = cfg.DataplaneMetricsPort }), err: true},
+		{name: "invalid-community", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.Communities[2] = "50000" }), err: true},
+		{name: "community-out-of-range", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.Communities[2] = "70000:1" }), err: true},
+		{name: "duplicate-community", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.Communities[2] = "50000:1000" }), err: true},
+		{name: "invalid-drain-community", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.DrainCommunity = "drain" }), err: true},
+		{name: "invalid-profiling-url", cfg: valid(func(cfg *meta.GatewayCtrlConfig) { cfg.ProfilingURL = "ftp://alloy:4040" }), err: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -5,9 +5,11 @@ package main
 
 import (
 	"crypto/tls"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -17,12 +19,19 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/lmittmann/tint"
 	"github.com/mattn/go-isatty"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	kctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	kyaml "sigs.k8s.io/yaml"
@@ -46,42 +55,118 @@ func init() {
 	// +kubebuilder:scaffold:scheme
 }
 
+type options struct {
+	configPath         string
+	configReload       time.Duration
+	metricsBindAddress string
+	metricsSecure      bool
+	metricsCertPath    string
+	metricsCertName    string
+	metricsCertKey     string
+	webhookCertPath    string
+	healthProbeAddress string
+	webhookPort        int
+	leaderElect        bool
+	leaderElectionID   string
+	logLevel           string
+	logFormat          string
+}
+
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
 func main() {
-	// TODO make it configurable
-	logLevel := slog.LevelDebug
+	opts := options{}
+	flag.StringVar(&opts.configPath, "config", "/etc/hedgehog/gateway-ctrl/config.yaml", "Path to the controller config file")
+	flag.DurationVar(&opts.configReload, "config-reload-interval", ctrl.DefaultConfigReloadInterval, "How often to check the config file for changes, 0 disables reloading")
+	flag.StringVar(&opts.metricsBindAddress, "metrics-bind-address", ":8080", "The address the metrics endpoint binds to, 0 disables it")
+	flag.BoolVar(&opts.metricsSecure, "metrics-secure", false, "Serve metrics over HTTPS with authentication and authorization")
+	flag.StringVar(&opts.metricsCertPath, "metrics-cert-path", "", "The directory with the metrics server certificate, self-signed one is generated if not set")
+	flag.StringVar(&opts.metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file")
+	flag.StringVar(&opts.metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file")
+	flag.StringVar(&opts.healthProbeAddress, "health-probe-bind-address", ":8081", "The address the health probe endpoint binds to")
+	flag.IntVar(&opts.webhookPort, "webhook-port", webhook.DefaultPort, "The port the webhook server listens on")
+	flag.StringVar(&opts.webhookCertPath, "webhook-cert-path", "", "The directory with the webhook server certificate, default one is used if not set")
+	flag.BoolVar(&opts.leaderElect, "leader-elect", true, "Enable leader election to ensure there is only one active controller")
+	flag.StringVar(&opts.leaderElectionID, "leader-election-id", "gateway.githedgehog.com", "The name of the leader election lease")
+	flag.StringVar(&opts.logLevel, "log-level", "info", "Log level: debug, info, warn or error")
+	flag.StringVar(&opts.logFormat, "log-format", logFormatText, "Log format: text or json")
+	flag.Parse()
+
+	logLevel := slog.LevelInfo
+	if err := logLevel.UnmarshalText([]byte(opts.logLevel)); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid log level %q, must be one of debug, info, warn or error\n", opts.logLevel)
+		os.Exit(1)
+	}
 
 	logW := os.Stderr
 
-	slog.SetDefault(slog.New(tint.NewHandler(logW, &tint.Options{
-		Level:      logLevel,
-		TimeFormat: time.StampMilli,
-		NoColor:    !isatty.IsTerminal(logW.Fd()),
-	})))
+	var logHandler, kubeHandler slog.Handler
+	switch opts.logFormat {
+	case logFormatText:
+		logHandler = tint.NewHandler(logW, &tint.Options{
+			Level:      logLevel,
+			TimeFormat: time.StampMilli,
+			NoColor:    !isatty.IsTerminal(logW.Fd()),
+		})
+		kubeHandler = tint.NewHandler(logW, &tint.Options{
+			Level:      max(logLevel, slog.LevelInfo),
+			TimeFormat: time.StampMilli,
+			NoColor:    !isatty.IsTerminal(logW.Fd()),
+		})
+	case logFormatJSON:
+		logHandler = slog.NewJSONHandler(logW, &slog.HandlerOptions{Level: logLevel})
+		kubeHandler = slog.NewJSONHandler(logW, &slog.HandlerOptions{Level: max(logLevel, slog.LevelInfo)})
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid log format %q, must be %s or %s\n", opts.logFormat, logFormatText, logFormatJSON)
+		os.Exit(1)
+	}
 
-	kubeHandler := tint.NewHandler(logW, &tint.Options{
-		Level:      slog.LevelInfo,
-		TimeFormat: time.StampMilli,
-		NoColor:    !isatty.IsTerminal(logW.Fd()),
-	})
+	slog.SetDefault(slog.New(logHandler))
 	kctrl.SetLogger(logr.FromSlogHandler(kubeHandler))
 	klog.SetSlogLogger(slog.New(kubeHandler))
 
-	if err := run(); err != nil {
+	if err := run(opts); err != nil {
 		slog.Error("Failed to run", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func run(opts options) error {
 	slog.Info("Starting gateway-ctrl", "version", version.Version)
 
-	cfgData, err := os.ReadFile("/etc/hedgehog/gateway-ctrl/config.yaml")
+	if opts.webhookPort <= 0 || opts.webhookPort > 65535 {
+		return fmt.Errorf("invalid webhook port %d, must be between 1 and 65535", opts.webhookPort) //nolint:goerr113
+	}
+	if opts.leaderElect && opts.leaderElectionID == "" {
+		return fmt.Errorf("leader election ID must be set if leader election is enabled") //nolint:goerr113
+	}
+	if opts.metricsCertPath != "" {
+		if !opts.metricsSecure {
+			return fmt.Errorf("metrics cert path is set but secure metrics are disabled") //nolint:goerr113
+		}
+		// fail fast instead of silently falling back to the self-signed certificate
+		for _, name := range []string{opts.metricsCertName, opts.metricsCertKey} {
+			if _, err := os.Stat(filepath.Join(opts.metricsCertPath, name)); err != nil {
+				return fmt.Errorf("checking metrics server certificate: %w", err)
+			}
+		}
+	}
+
+	cfgData, err := os.ReadFile(opts.configPath)
 	if err != nil {
 		return fmt.Errorf("reading config file: %w", err)
 	}
 	cfg := &meta.GatewayCtrlConfig{}
 	if err := kyaml.Unmarshal(cfgData, cfg); err != nil {
-		return fmt.Errorf("unmarshalling config file: %w", err)
+		return fmt.Errorf("unmarshalling config file %s: %w", opts.configPath, err)
+	}
+	// validated by the config store
+	cfgs, err := ctrl.NewConfigStore(cfg)
+	if err != nil {
+		return fmt.Errorf("loading config file %s: %w", opts.configPath, err)
 	}
 
 	// Disabling http/2 will prevent from being vulnerable to the HTTP/2 Stream Cancellation and Rapid Reset CVEs.
@@ -95,26 +180,57 @@ func run() error {
 		},
 	}
 
-	// TODO: enable secure metrics
-	// FilterProvider is used to protect the metrics endpoint with authn/authz.
-	// These configurations ensure that only authorized users and service accounts
-	// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
-	// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/metrics/filters#WithAuthenticationAndAuthorization
-	// metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+	metricsServerOptions := metricsserver.Options{
+		BindAddress:   opts.metricsBindAddress,
+		SecureServing: opts.metricsSecure,
+		TLSOpts:       tlsOpts,
+	}
+	if opts.metricsSecure {
+		// FilterProvider is used to protect the metrics endpoint with authn/authz.
+		// These configurations ensure that only authorized users and service accounts
+		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
+		// https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/metrics/filters#WithAuthenticationAndAuthorization
+		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+
+		// certificates are watched and reloaded on rotation if provided, otherwise self-signed ones are generated
+		if opts.metricsCertPath != "" {
+			slog.Info("Using metrics server certificate", "path", opts.metricsCertPath)
+
+			metricsServerOptions.CertDir = opts.metricsCertPath
+			metricsServerOptions.CertName = opts.metricsCertName
+			metricsServerOptions.KeyName = opts.metricsCertKey
+		}
+	}
+
+	// only secrets with BGP passwords in the gateway namespace and the ones for gateway pods are needed
+	gatewayNamespaces := map[string]cache.Config{
+		kmetav1.NamespaceDefault: {},
+	}
+	if cfg.Namespace != "" {
+		gatewayNamespaces[cfg.Namespace] = cache.Config{}
+	}
 
 	mgr, err := kctrl.NewManager(kctrl.GetConfigOrDie(), kctrl.Options{
 		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: ":8080",
-			TLSOpts:     tlsOpts,
+		Cache: cache.Options{
+			ByObject: map[kclient.Object]cache.ByObject{
+				// only watch per-gateway resources and referenced secrets in the namespaces used by the controller
+				&corev1.Secret{}:         {Namespaces: gatewayNamespaces},
+				&corev1.ServiceAccount{}: {Namespaces: gatewayNamespaces},
+				&appv1.DaemonSet{}:       {Namespaces: gatewayNamespaces},
+				&rbacv1.Role{}:           {Namespaces: gatewayNamespaces},
+				&rbacv1.RoleBinding{}:    {Namespaces: gatewayNamespaces},
+			},
 		},
+		Metrics: metricsServerOptions,
 		WebhookServer: webhook.NewServer(webhook.Options{
-			Port:    9443,
+			Port:    opts.webhookPort,
+			CertDir: opts.webhookCertPath,
 			TLSOpts: tlsOpts,
 		}),
-		HealthProbeBindAddress: ":8081",
-		LeaderElection:         true,
-		LeaderElectionID:       "gateway.githedgehog.com",
+		HealthProbeBindAddress: opts.healthProbeAddress,
+		LeaderElection:         opts.leaderElect,
+		LeaderElectionID:       opts.leaderElectionID,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -132,7 +248,7 @@ func run() error {
 	}
 
 	// Controllers
-	if err := ctrl.SetupGatewayReconcilerWith(mgr, cfg); err != nil {
+	if err := ctrl.SetupGatewayReconcilerWith(mgr, cfgs); err != nil {
 		return fmt.Errorf("setting up gateway controller: %w", err)
 	}
 	if err := ctrl.SetupVPCInfoReconcilerWith(mgr); err != nil {
@@ -141,9 +257,12 @@ func run() error {
 	if err := ctrl.SetupPeeringReconcilerWith(mgr); err != nil {
 		return fmt.Errorf("setting up peering controller: %w", err)
 	}
+	if err := ctrl.SetupGatewayGroupReconcilerWith(mgr, cfgs); err != nil {
+		return fmt.Errorf("setting up gatewaygroup controller: %w", err)
+	}
 
 	// Webhooks
-	if err := ctrl.SetupGatewayWebhookWith(mgr, cfg); err != nil {
+	if err := ctrl.SetupGatewayWebhookWith(mgr, cfgs); err != nil {
 		return fmt.Errorf("setting up gateway webhook: %w", err)
 	}
 	if err := ctrl.SetupGatewayGroupWebhookWith(mgr); err != nil {
@@ -158,6 +277,16 @@ func run() error {
 
 	// +kubebuilder:scaffold:builder
 
+	if opts.configReload > 0 {
+		// pod name and namespace are provided using the downward API to attach reload failure events to the pod
+		reloader := ctrl.NewConfigReloader(opts.configPath, cfgData, cfgs, mgr.GetEventRecorder(ctrl.EventsReporter),
+			os.Getenv("POD_NAMESPACE"), os.Getenv("POD_NAME"))
+		reloader.Interval = opts.configReload
+		if err := mgr.Add(reloader); err != nil {
+			return fmt.Errorf("setting up config reloader: %w", err)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		return fmt.Errorf("setting up health check: %w", err)
 	}
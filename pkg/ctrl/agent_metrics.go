@@ -0,0 +1,156 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const agentMetricsSubsystem = "agent"
+
+func agentDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, agentMetricsSubsystem, name),
+		help,
+		append([]string{"gateway"}, labels...), nil,
+	)
+}
+
+var (
+	agentHeartbeatAgeDesc = agentDesc("heartbeat_age_seconds", "Time since the last heartbeat of the gateway agent")
+
+	agentVPCPacketsDesc = agentDesc("vpc_packets_total", "Number of packets sent on the VPC", "vpc")
+	agentVPCBytesDesc   = agentDesc("vpc_bytes_total", "Number of bytes sent on the VPC", "vpc")
+	agentVPCDropsDesc   = agentDesc("vpc_drops_total", "Number of packets dropped on the VPC", "vpc")
+
+	agentPeeringPacketsDesc = agentDesc("peering_packets_total", "Number of packets sent on the peering in one direction", "src_vpc", "dst_vpc")
+	agentPeeringBytesDesc   = agentDesc("peering_bytes_total", "Number of bytes sent on the peering in one direction", "src_vpc", "dst_vpc")
+	agentPeeringDropsDesc   = agentDesc("peering_drops_total", "Number of packets dropped on the peering in one direction", "src_vpc", "dst_vpc")
+	agentPeeringPPSDesc     = agentDesc("peering_packets_per_second", "Packets per second sent on the peering in one direction", "src_vpc", "dst_vpc")
+	agentPeeringBPSDesc     = agentDesc("peering_bytes_per_second", "Bytes per second sent on the peering in one direction", "src_vpc", "dst_vpc")
+
+	agentBGPSessionStateDesc = agentDesc("bgp_neighbor_session_state", "BGP session state of the neighbor, 1 for the current state", "vrf", "neighbor", "state")
+	agentBGPEnabledDesc      = agentDesc("bgp_neighbor_enabled", "Whether the BGP neighbor is enabled", "vrf", "neighbor")
+	agentBGPTransitionsDesc  = agentDesc("bgp_neighbor_established_transitions_total", "Number of transitions of the BGP session to established", "vrf", "neighbor")
+	agentBGPDroppedDesc      = agentDesc("bgp_neighbor_connections_dropped_total", "Number of dropped BGP connections", "vrf", "neighbor")
+	agentBGPPrefixesDesc     = agentDesc("bgp_neighbor_prefixes", "Number of prefixes exchanged with the BGP neighbor", "vrf", "neighbor", "afi", "direction")
+)
+
+var bgpSessionStates = []gwintapi.BGPNeighborSessionState{
+	gwintapi.BGPStateUnset,
+	gwintapi.BGPStateIdle,
+	gwintapi.BGPStateConnect,
+	gwintapi.BGPStateActive,
+	gwintapi.BGPStateOpen,
+	gwintapi.BGPStateEstablished,
+}
+
+// agentStateCollector reports the state collected by the gateway agents on scrape so it's available without scraping
+// the gateway nodes directly
+type agentStateCollector struct {
+	kclient.Reader
+	now func() time.Time
+}
+
+var _ prometheus.Collector = (*agentStateCollector)(nil)
+
+func (c *agentStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		agentHeartbeatAgeDesc,
+		agentVPCPacketsDesc, agentVPCBytesDesc, agentVPCDropsDesc,
+		agentPeeringPacketsDesc, agentPeeringBytesDesc, agentPeeringDropsDesc, agentPeeringPPSDesc, agentPeeringBPSDesc,
+		agentBGPSessionStateDesc, agentBGPEnabledDesc, agentBGPTransitionsDesc, agentBGPDroppedDesc, agentBGPPrefixesDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *agentStateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryCollectTimeout)
+	defer cancel()
+
+	gwAgs := &gwintapi.GatewayAgentList{}
+	if err := c.List(ctx, gwAgs); err != nil {
+		slog.Debug("Failed to list gateway agents for metrics", "error", err)
+
+		return
+	}
+
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+
+	for _, gwAg := range gwAgs.Items {
+		gw := gwAg.Name
+		status := gwAg.Status
+
+		if !status.LastHeartbeat.IsZero() {
+			ch <- prometheus.MustNewConstMetric(agentHeartbeatAgeDesc, prometheus.GaugeValue, now.Sub(status.LastHeartbeat.Time).Seconds(), gw)
+		}
+
+		for vpc, st := range status.State.VPCs {
+			ch <- prometheus.MustNewConstMetric(agentVPCPacketsDesc, prometheus.CounterValue, float64(st.Packets), gw, vpc)
+			ch <- prometheus.MustNewConstMetric(agentVPCBytesDesc, prometheus.CounterValue, float64(st.Bytes), gw, vpc)
+			ch <- prometheus.MustNewConstMetric(agentVPCDropsDesc, prometheus.CounterValue, float64(st.Drops), gw, vpc)
+		}
+
+		for key, st := range status.State.Peerings {
+			// key is VPC1->VPC2 for the traffic from VPC1 to VPC2
+			src, dst, ok := strings.Cut(key, "->")
+			if !ok {
+				slog.Debug("Skipping peering status with unexpected key", "gateway", gw, "key", key)
+
+				continue
+			}
+
+			ch <- prometheus.MustNewConstMetric(agentPeeringPacketsDesc, prometheus.CounterValue, float64(st.Packets), gw, src, dst)
+			ch <- prometheus.MustNewConstMetric(agentPeeringBytesDesc, prometheus.CounterValue, float64(st.Bytes), gw, src, dst)
+			ch <- prometheus.MustNewConstMetric(agentPeeringDropsDesc, prometheus.CounterValue, float64(st.Drops), gw, src, dst)
+			ch <- prometheus.MustNewConstMetric(agentPeeringPPSDesc, prometheus.GaugeValue, st.PktsPerSecond, gw, src, dst)
+			ch <- prometheus.MustNewConstMetric(agentPeeringBPSDesc, prometheus.GaugeValue, st.BytesPerSecond, gw, src, dst)
+		}
+
+		for vrf, vrfSt := range status.State.BGP.VRFs {
+			for neigh, st := range vrfSt.Neighbors {
+				state := st.SessionState
+				if state == "" {
+					state = gwintapi.BGPStateUnset
+				}
+				for _, s := range bgpSessionStates {
+					ch <- prometheus.MustNewConstMetric(agentBGPSessionStateDesc, prometheus.GaugeValue, boolValue(s == state), gw, vrf, neigh, string(s))
+				}
+
+				ch <- prometheus.MustNewConstMetric(agentBGPEnabledDesc, prometheus.GaugeValue, boolValue(st.Enabled), gw, vrf, neigh)
+				ch <- prometheus.MustNewConstMetric(agentBGPTransitionsDesc, prometheus.CounterValue, float64(st.EstablishedTransitions), gw, vrf, neigh)
+				ch <- prometheus.MustNewConstMetric(agentBGPDroppedDesc, prometheus.CounterValue, float64(st.ConnectionsDropped), gw, vrf, neigh)
+
+				for afi, prefixes := range map[string]gwintapi.BGPNeighborPrefixes{
+					"ipv4_unicast": st.IPv4UnicastPrefixes,
+					"ipv6_unicast": st.IPv6UnicastPrefixes,
+					"l2vpn_evpn":   st.L2VPNEVPNPrefixes,
+				} {
+					ch <- prometheus.MustNewConstMetric(agentBGPPrefixesDesc, prometheus.GaugeValue, float64(prefixes.Received), gw, vrf, neigh, afi, "received")
+					ch <- prometheus.MustNewConstMetric(agentBGPPrefixesDesc, prometheus.GaugeValue, float64(prefixes.ReceivedPrePolicy), gw, vrf, neigh, afi, "received_pre_policy")
+					ch <- prometheus.MustNewConstMetric(agentBGPPrefixesDesc, prometheus.GaugeValue, float64(prefixes.Sent), gw, vrf, neigh, afi, "sent")
+				}
+			}
+		}
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAgentStateCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwintapi.AddToScheme(scheme))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	gwAg := &gwintapi.GatewayAgent{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gw-1"},
+		Status: gwintapi.GatewayAgentStatus{
+			LastHeartbeat: kmetav1.NewTime(now.Add(-15 * time.Second)),
+			State: gwintapi.GatewayState{
+				VPCs: map[string]gwintapi.VPCStatus{
+					"vpc-1": {Packets: 100, Bytes: 6400, Drops: 1},
+				},
+				Peerings: map[string]gwintapi.PeeringStatus{
+					"vpc-1->vpc-2": {Packets: 50, Bytes: 3200, PktsPerSecond: 5},
+					"broken":       {Packets: 1},
+				},
+				BGP: gwintapi.BGPStatus{
+					VRFs: map[string]gwintapi.BGPVRFStatus{
+						"default": {
+							Neighbors: map[string]gwintapi.BGPNeighborStatus{
+								"172.30.128.1": {
+									Enabled:                true,
+									SessionState:           gwintapi.BGPStateEstablished,
+									EstablishedTransitions: 2,
+									L2VPNEVPNPrefixes:      gwintapi.BGPNeighborPrefixes{Received: 10, Sent: 3},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c := &agentStateCollector{
+		Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(gwAg).Build(),
+		now:    func() time.Time { return now },
+	}
+
+	expected := `
+# HELP gateway_ctrl_agent_heartbeat_age_seconds Time since the last heartbeat of the gateway agent
+# TYPE gateway_ctrl_agent_heartbeat_age_seconds gauge
+gateway_ctrl_agent_heartbeat_age_seconds{gateway="gw-1"} 15
+# HELP gateway_ctrl_agent_vpc_packets_total Number of packets sent on the VPC
+# TYPE gateway_ctrl_agent_vpc_packets_total counter
+gateway_ctrl_agent_vpc_packets_total{gateway="gw-1",vpc="vpc-1"} 100
+# HELP gateway_ctrl_agent_peering_packets_total Number of packets sent on the peering in one direction
+# TYPE gateway_ctrl_agent_peering_packets_total counter
+gateway_ctrl_agent_peering_packets_total{dst_vpc="vpc-2",gateway="gw-1",src_vpc="vpc-1"} 50
+# HELP gateway_ctrl_agent_bgp_neighbor_session_state BGP session state of the neighbor, 1 for the current state
+# TYPE gateway_ctrl_agent_bgp_neighbor_session_state gauge
+gateway_ctrl_agent_bgp_neighbor_session_state{gateway="gw-1",neighbor="172.30.128.1",state="active",vrf="default"} 0
+gateway_ctrl_agent_bgp_neighbor_session_state{gateway="gw-1",neighbor="172.30.128.1",state="connect",vrf="default"} 0
+gateway_ctrl_agent_bgp_neighbor_session_state{gateway="gw-1",neighbor="172.30.128.1",state="established",vrf="default"} 1
+gateway_ctrl_agent_bgp_neighbor_session_state{gateway="gw-1",neighbor="172.30.128.1",state="idle",vrf="default"} 0
+gateway_ctrl_agent_bgp_neighbor_session_state{gateway="gw-1",neighbor="172.30.128.1",state="open",vrf="default"} 0
+gateway_ctrl_agent_bgp_neighbor_session_state{gateway="gw-1",neighbor="172.30.128.1",state="unset",vrf="default"} 0
+`
+
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"gateway_ctrl_agent_heartbeat_age_seconds",
+		"gateway_ctrl_agent_vpc_packets_total",
+		"gateway_ctrl_agent_peering_packets_total",
+		"gateway_ctrl_agent_bgp_neighbor_session_state",
+	))
+
+	// 1 vpc * 3 + 1 peering * 5 + 6 states + 3 per neighbor + 3 afis * 3 directions + heartbeat
+	require.Equal(t, 3+5+6+3+9+1, testutil.CollectAndCount(c))
+}
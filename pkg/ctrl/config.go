@@ -0,0 +1,189 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	kyaml "sigs.k8s.io/yaml"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/api/meta"
+)
+
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+const DefaultConfigReloadInterval = 10 * time.Second
+
+// ConfigStore holds the current controller config, it's safe to be read while being replaced on reload
+type ConfigStore struct {
+	cfg  atomic.Pointer[meta.GatewayCtrlConfig]
+	m    sync.Mutex
+	subs []chan event.GenericEvent
+}
+
+func NewConfigStore(cfg *meta.GatewayCtrlConfig) (*ConfigStore, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("gateway controller config is nil") //nolint:goerr113
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid gateway controller config: %w", err)
+	}
+
+	s := &ConfigStore{}
+	s.cfg.Store(cfg)
+
+	return s, nil
+}
+
+// Get returns the current config, it should be called once per reconcile/request and never modified
+func (s *ConfigStore) Get() *meta.GatewayCtrlConfig {
+	return s.cfg.Load()
+}
+
+// subscribe returns a channel notified on each config change to be used as a controller source
+func (s *ConfigStore) subscribe() <-chan event.GenericEvent {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	ch := make(chan event.GenericEvent, 1)
+	s.subs = append(s.subs, ch)
+
+	return ch
+}
+
+// Update validates the new config and replaces the current one with it, the previous config is kept on error
+func (s *ConfigStore) Update(cfg *meta.GatewayCtrlConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid gateway controller config: %w", err)
+	}
+
+	// cache is limited to the gateway namespace and resources in the old one would be left behind
+	if old := s.Get(); old.Namespace != cfg.Namespace {
+		return fmt.Errorf("namespace can't be changed from %q to %q without restart", old.Namespace, cfg.Namespace) //nolint:goerr113
+	}
+
+	s.cfg.Store(cfg)
+
+	// notify the controllers to roll out the new config, it's enough to have a single pending notification
+	s.m.Lock()
+	defer s.m.Unlock()
+	for _, ch := range s.subs {
+		select {
+		case ch <- event.GenericEvent{Object: &gwapi.Gateway{}}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// ConfigReloader periodically checks the config file and loads it into the store on change
+type ConfigReloader struct {
+	Path     string
+	Interval time.Duration
+	Store    *ConfigStore
+	Recorder events.EventRecorder
+	// Pod is the object reload failure events are attached to, events aren't recorded if it's nil
+	Pod *corev1.Pod
+
+	last []byte
+}
+
+// NewConfigReloader creates a reloader for the already loaded config file, pod name and namespace are optional
+func NewConfigReloader(path string, data []byte, store *ConfigStore, recorder events.EventRecorder, podNs, podName string) *ConfigReloader {
+	r := &ConfigReloader{
+		Path:     path,
+		Interval: DefaultConfigReloadInterval,
+		Store:    store,
+		Recorder: recorder,
+		last:     data,
+	}
+	if podNs != "" && podName != "" {
+		r.Pod = &corev1.Pod{
+			TypeMeta:   kmetav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: podNs, Name: podName},
+		}
+	}
+
+	return r
+}
+
+// NeedLeaderElection is false as the webhooks are served by all replicas and need the up to date config too
+func (r *ConfigReloader) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *ConfigReloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.reload()
+		}
+	}
+}
+
+// reload loads the config file if it's changed since the last attempt, returns true if the config was replaced
+func (r *ConfigReloader) reload() bool {
+	data, err := os.ReadFile(r.Path)
+	if err != nil {
+		// only report once until the file is readable again
+		if r.last != nil {
+			r.failed(fmt.Errorf("reading config file: %w", err))
+		}
+		r.last = nil
+
+		return false
+	}
+	if bytes.Equal(data, r.last) {
+		return false
+	}
+	// don't retry the same broken content on every tick
+	r.last = data
+
+	cfg := &meta.GatewayCtrlConfig{}
+	if err := kyaml.Unmarshal(data, cfg); err != nil {
+		r.failed(fmt.Errorf("unmarshalling config file: %w", err))
+
+		return false
+	}
+	if err := r.Store.Update(cfg); err != nil {
+		r.failed(err)
+
+		return false
+	}
+
+	configReloads.WithLabelValues(configReloadSuccess).Inc()
+	configLastReloadSuccess.Set(1)
+	slog.Info("Config reloaded", "path", r.Path)
+
+	return true
+}
+
+func (r *ConfigReloader) failed(err error) {
+	configReloads.WithLabelValues(configReloadError).Inc()
+	configLastReloadSuccess.Set(0)
+	slog.Error("Failed to reload config, keeping the previous one", "path", r.Path, "error", err)
+
+	if r.Recorder != nil && r.Pod != nil {
+		r.Recorder.Eventf(r.Pod, nil, corev1.EventTypeWarning, EventReasonConfigReloadFailed, "Reload",
+			"Failed to reload config %s, keeping the previous one: %s", r.Path, err.Error())
+	}
+}
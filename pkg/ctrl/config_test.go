@@ -0,0 +1,96 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.githedgehog.com/gateway/api/meta"
+	kyaml "sigs.k8s.io/yaml"
+)
+
+func TestConfigReload(t *testing.T) {
+	cfg := func(f ...func(cfg *meta.GatewayCtrlConfig)) *meta.GatewayCtrlConfig {
+		cfg := &meta.GatewayCtrlConfig{
+			Namespace:            "fab",
+			DataplaneRef:         "registry.local:31000/githedgehog/dataplane:v1",
+			FRRRef:               "registry.local:31000/githedgehog/dpdk-sys/frr:v1",
+			ToolboxRef:           "registry.local:31000/githedgehog/toolbox:v1",
+			DataplaneMetricsPort: 9442,
+			FRRMetricsPort:       9342,
+		}
+		for _, fn := range f {
+			fn(cfg)
+		}
+
+		return cfg
+	}
+
+	marshal := func(cfg *meta.GatewayCtrlConfig) []byte {
+		data, err := kyaml.Marshal(cfg)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	for _, tt := range []struct {
+		name     string
+		data     []byte
+		reloaded bool
+		expected *meta.GatewayCtrlConfig
+	}{
+		{
+			name:     "unchanged",
+			data:     marshal(cfg()),
+			expected: cfg(),
+		},
+		{
+			name:     "new-images",
+			data:     marshal(cfg(func(cfg *meta.GatewayCtrlConfig) { cfg.DataplaneRef = "registry.local:31000/githedgehog/dataplane:v2" })),
+			reloaded: true,
+			expected: cfg(func(cfg *meta.GatewayCtrlConfig) { cfg.DataplaneRef = "registry.local:31000/githedgehog/dataplane:v2" }),
+		},
+		{
+			name:     "invalid",
+			data:     marshal(cfg(func(cfg *meta.GatewayCtrlConfig) { cfg.FRRMetricsPort = cfg.DataplaneMetricsPort })),
+			expected: cfg(),
+		},
+		{
+			name:     "namespace-changed",
+			data:     marshal(cfg(func(cfg *meta.GatewayCtrlConfig) { cfg.Namespace = "other" })),
+			expected: cfg(),
+		},
+		{
+			name:     "broken-yaml",
+			data:     []byte("namespace: [fab"),
+			expected: cfg(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			data := marshal(cfg())
+			require.NoError(t, os.WriteFile(path, data, 0o600))
+
+			cfgs, err := NewConfigStore(cfg())
+			require.NoError(t, err)
+			r := NewConfigReloader(path, data, cfgs, nil, "", "")
+			changes := cfgs.subscribe()
+
+			require.NoError(t, os.WriteFile(path, tt.data, 0o600))
+			require.Equal(t, tt.reloaded, r.reload())
+			require.Equal(t, tt.expected, cfgs.Get())
+			if tt.reloaded {
+				require.Len(t, changes, 1, "gateways should be enqueued")
+			} else {
+				require.Empty(t, changes)
+			}
+
+			// the same content shouldn't be loaded again
+			require.False(t, r.reload())
+		})
+	}
+}
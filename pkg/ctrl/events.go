@@ -0,0 +1,26 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+// EventsReporter is the name of the controller reporting events
+const EventsReporter = "gateway-ctrl"
+
+// Reasons for the events emitted by the controllers
+const (
+	EventReasonWaitingForVPCInfo = "WaitingForVPCInfo"
+	EventReasonAgentUpdateFailed = "AgentUpdateFailed"
+	EventReasonDeployFailed      = "DeployFailed"
+	EventReasonMoved             = "Moved"
+	EventReasonReady             = "Ready"
+	EventReasonNotReady          = "NotReady"
+
+	EventReasonVPCNotFound    = "VPCNotFound"
+	EventReasonPlaced         = "Placed"
+	EventReasonNoGatewayGroup = "NoGatewayGroup"
+
+	EventReasonIDAllocated     = "IDAllocated"
+	EventReasonIDPoolExhausted = "IDPoolExhausted"
+
+	EventReasonConfigReloadFailed = "ConfigReloadFailed"
+)
@@ -6,8 +6,12 @@ package ctrl
 import (
 	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"maps"
+	"net/netip"
 	"path/filepath"
 	"reflect"
 	"slices"
@@ -22,16 +26,25 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	ktypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	kctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -40,6 +53,8 @@ const (
 	frrRunVolumeName       = "frr-run"
 	frrTmpVolumeName       = "frr-tmp"
 	frrRootRunVolumeName   = "frr-root-run"
+	bgpAuthVolumeName      = "bgp-auth"
+	hugepagesVolumeName    = "hugepages"
 
 	dataplaneRunHostPath = "/run/hedgehog/dataplane"
 	frrRunHostPath       = "/run/hedgehog/frr"
@@ -47,8 +62,19 @@ const (
 	dataplaneRunMountPath = "/var/run/dataplane"
 	frrRunMountPath       = "/var/run/frr"
 	frrRootRunMountPath   = "/run/frr"
+	bgpAuthMountPath      = "/etc/hedgehog/bgp-auth"
+	hugepagesMountPath    = "/dev/hugepages"
 	cpiSocket             = "hh/dataplane.sock"
 	frrAgentSocket        = "frr-agent.sock"
+
+	bgpAuthHashAnnotation      = "gateway.githedgehog.com/bgp-auth-hash"
+	gatewayPlacementAnnotation = "gateway.githedgehog.com/placement"
+
+	// gatewayLabel is set on all per-gateway resources to the gateway name to trigger reconciliation on changes
+	gatewayLabel = "gateway.githedgehog.com/gateway"
+	// gatewayFinalizer is used to clean up the per-gateway resources in the gateway namespace which can't be
+	// garbage collected using owner references
+	gatewayFinalizer = "gateway.githedgehog.com/cleanup"
 )
 
 // +kubebuilder:rbac:groups=gwint.githedgehog.com,resources=gatewayagents,verbs=get;list;watch;create;update;patch;delete
@@ -57,12 +83,15 @@ const (
 
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gateways,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gateways/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gateways/finalizers,verbs=update
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gatewaygroups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=vpcinfos,verbs=get;list;watch
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
 
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
@@ -70,24 +99,62 @@ const (
 
 type GatewayReconciler struct {
 	kclient.Client
-	cfg *meta.GatewayCtrlConfig
+	cfgs        *ConfigStore
+	propagation *peeringPropagation
+	vpcWaits    vpcInfoWaits
+	recorder    events.EventRecorder
 }
 
-func SetupGatewayReconcilerWith(mgr kctrl.Manager, cfg *meta.GatewayCtrlConfig) error {
-	if cfg == nil {
-		return fmt.Errorf("gateway controller config is nil") //nolint:goerr113
+func SetupGatewayReconcilerWith(mgr kctrl.Manager, cfgs *ConfigStore) error {
+	if cfgs == nil {
+		return fmt.Errorf("gateway controller config store is nil") //nolint:goerr113
 	}
 
 	r := &GatewayReconciler{
 		Client: mgr.GetClient(),
-		cfg:    cfg,
+		cfgs:   cfgs,
+		propagation: newPeeringPropagation(func(d time.Duration) {
+			peeringPropagationDuration.Observe(d.Seconds())
+		}),
+		recorder: mgr.GetEventRecorder(EventsReporter),
+	}
+
+	if err := metrics.Registry.Register(&inventoryCollector{Reader: mgr.GetClient()}); err != nil {
+		return fmt.Errorf("registering inventory metrics: %w", err)
+	}
+	if err := metrics.Registry.Register(&agentStateCollector{Reader: mgr.GetClient()}); err != nil {
+		return fmt.Errorf("registering gateway agent metrics: %w", err)
 	}
 
 	if err := kctrl.NewControllerManagedBy(mgr).
 		Named("Gateway").
-		For(&gwapi.Gateway{}).
-		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		// status updates (including our own) are ignored, only spec and metadata changes are reconciled
+		For(&gwapi.Gateway{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+			predicate.LabelChangedPredicate{},
+		))).
+		// gateway agent has the same name and namespace as the gateway, heartbeats are ignored and stale heartbeats
+		// are caught by the periodic requeue
+		Watches(&gwintapi.GatewayAgent{}, &handler.EnqueueRequestForObject{},
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: agentStatusChanged})).
+		// images held back for the canary are released once it's ready and running them
+		Watches(&gwapi.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.enqueueGatewaysForCanary),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: canaryStatusChanged})).
+		// traffic, propagation and health reported in the status don't affect the gateway config
+		Watches(&gwapi.GatewayGroup{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: gatewayConfigInputChanged})).
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: gatewayConfigInputChanged})).
 		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.enqueueGatewaysForSecret)).
+		// per-gateway resources to revert any manual changes
+		Watches(&appv1.DaemonSet{}, handler.EnqueueRequestsFromMapFunc(enqueueGatewayByLabel)).
+		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(enqueueGatewayByLabel)).
+		Watches(&rbacv1.Role{}, handler.EnqueueRequestsFromMapFunc(enqueueGatewayByLabel)).
+		Watches(&rbacv1.RoleBinding{}, handler.EnqueueRequestsFromMapFunc(enqueueGatewayByLabel)).
+		// roll out config changes to all gateways
+		WatchesRawSource(source.Channel(cfgs.subscribe(), handler.EnqueueRequestsFromMapFunc(r.enqueueAllGateways))).
 		Complete(r); err != nil {
 		return fmt.Errorf("setting up controller: %w", err)
 	}
@@ -115,6 +182,141 @@ func (r *GatewayReconciler) enqueueAllGateways(ctx context.Context, obj kclient.
 	return res
 }
 
+func (r *GatewayReconciler) enqueueGatewaysForCanary(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	gwGroups := &gwapi.GatewayGroupList{}
+	if err := r.List(ctx, gwGroups, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing gateway groups to reconcile for canary")
+
+		return nil
+	}
+
+	canaryOf := map[string]bool{}
+	for _, gwGroup := range gwGroups.Items {
+		if gwGroup.Spec.Canary == obj.GetName() {
+			canaryOf[gwGroup.Name] = true
+		}
+	}
+	if len(canaryOf) == 0 {
+		return nil
+	}
+
+	gws := &gwapi.GatewayList{}
+	if err := r.List(ctx, gws, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing gateways to reconcile for canary")
+
+		return nil
+	}
+
+	res := []reconcile.Request{}
+	for _, gw := range gws.Items {
+		if gw.Name == obj.GetName() {
+			continue
+		}
+
+		for _, membership := range gw.Spec.Groups {
+			if canaryOf[membership.Name] {
+				res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+					Namespace: gw.Namespace,
+					Name:      gw.Name,
+				}})
+
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// canaryStatusChanged returns true if the gateway images or readiness used to release images held back for the
+// canary are changed
+func canaryStatusChanged(evt event.UpdateEvent) bool {
+	oldGw, ok := evt.ObjectOld.(*gwapi.Gateway)
+	if !ok {
+		return true
+	}
+	newGw, ok := evt.ObjectNew.(*gwapi.Gateway)
+	if !ok {
+		return true
+	}
+
+	return oldGw.Status.Images != newGw.Status.Images ||
+		kmeta.IsStatusConditionTrue(oldGw.Status.Conditions, gwapi.GatewayConditionReady) !=
+			kmeta.IsStatusConditionTrue(newGw.Status.Conditions, gwapi.GatewayConditionReady)
+}
+
+// gatewayConfigInputChanged returns true if the spec, deletion or the status fields used to build the gateway agent
+// config (peering placement and group member communities) are changed
+func gatewayConfigInputChanged(evt event.UpdateEvent) bool {
+	if evt.ObjectOld == nil || evt.ObjectNew == nil {
+		return true
+	}
+	if evt.ObjectOld.GetGeneration() != evt.ObjectNew.GetGeneration() ||
+		!evt.ObjectOld.GetDeletionTimestamp().Equal(evt.ObjectNew.GetDeletionTimestamp()) {
+		return true
+	}
+
+	switch oldObj := evt.ObjectOld.(type) {
+	case *gwapi.Peering:
+		newObj, ok := evt.ObjectNew.(*gwapi.Peering)
+
+		return !ok || oldObj.Status.GatewayGroup != newObj.Status.GatewayGroup
+	case *gwapi.GatewayGroup:
+		newObj, ok := evt.ObjectNew.(*gwapi.GatewayGroup)
+
+		return !ok || !maps.Equal(oldObj.Status.Communities, newObj.Status.Communities)
+	default:
+		return true
+	}
+}
+
+func enqueueGatewayByLabel(_ context.Context, obj kclient.Object) []reconcile.Request {
+	gwName := obj.GetLabels()[gatewayLabel]
+	if gwName == "" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: ktypes.NamespacedName{
+		Namespace: kmetav1.NamespaceDefault,
+		Name:      gwName,
+	}}}
+}
+
+func (r *GatewayReconciler) enqueueGatewaysForSecret(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	// secrets managed by the controller itself
+	if res := enqueueGatewayByLabel(ctx, obj); len(res) > 0 {
+		return res
+	}
+
+	if obj.GetNamespace() != kmetav1.NamespaceDefault {
+		return nil
+	}
+
+	res := []reconcile.Request{}
+
+	gws := &gwapi.GatewayList{}
+	if err := r.List(ctx, gws); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing gateways to reconcile for secret")
+
+		return nil
+	}
+
+	for _, gw := range gws.Items {
+		for _, neigh := range gw.Spec.Neighbors {
+			if neigh.Auth != nil && neigh.Auth.SecretRef.Name == obj.GetName() {
+				res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+					Namespace: gw.Namespace,
+					Name:      gw.Name,
+				}})
+
+				break
+			}
+		}
+	}
+
+	return res
+}
+
 func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
 	l := kctrllog.FromContext(ctx)
 
@@ -124,6 +326,9 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 		return kctrl.Result{}, nil
 	}
 
+	// the same config is used for the whole reconcile even if it's reloaded in the meantime
+	cfg := r.cfgs.Get()
+
 	gw := &gwapi.Gateway{}
 	if err := r.Get(ctx, req.NamespacedName, gw); err != nil {
 		if kapierrors.IsNotFound(err) {
@@ -134,11 +339,33 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 	}
 
 	if gw.DeletionTimestamp != nil {
-		l.Info("Gateway is being deleted, skipping")
+		if !ctrlutil.ContainsFinalizer(gw, gatewayFinalizer) {
+			return kctrl.Result{}, nil
+		}
+
+		l.Info("Gateway is being deleted, cleaning up")
+
+		if err := r.cleanupGateway(ctx, cfg, gw); err != nil {
+			return kctrl.Result{}, fmt.Errorf("cleaning up gateway: %w", err)
+		}
+
+		forgetGatewayMetrics(gw.Name)
+		r.propagation.forget(gw.Name, time.Now())
+		r.vpcWaits.done(gw.Name)
+
+		ctrlutil.RemoveFinalizer(gw, gatewayFinalizer)
+		if err := r.Update(ctx, gw); err != nil {
+			return kctrl.Result{}, fmt.Errorf("removing gateway finalizer: %w", err)
+		}
 
 		return kctrl.Result{}, nil
 	}
 
+	start := time.Now()
+	defer func() {
+		gatewayReconcileDuration.WithLabelValues(gw.Name).Observe(time.Since(start).Seconds())
+	}()
+
 	{
 		defGwGr := &gwapi.GatewayGroup{
 			ObjectMeta: kmetav1.ObjectMeta{
@@ -154,8 +381,11 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 
 		orig := gw.DeepCopy()
 		gw.Default()
+		ctrlutil.AddFinalizer(gw, gatewayFinalizer)
+		// confirmation is only valid for a single update
+		delete(gw.Annotations, gwapi.GatewayConfirmAnnotation)
 		if !reflect.DeepEqual(orig, gw) {
-			l.Info("Applying defaults to Gateway")
+			l.Info("Applying defaults and finalizer to Gateway")
 
 			if err := r.Update(ctx, gw); err != nil {
 				return kctrl.Result{}, fmt.Errorf("updating gateway: %w", err)
@@ -170,22 +400,56 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 		inGwGroups[gr.Name] = true
 	}
 	gwGroups := map[string]gwintapi.GatewayGroupInfo{}
+	gwGroupComms := map[string]map[string]uint32{}
+	gwGroupList := &gwapi.GatewayGroupList{}
+	if err := r.List(ctx, gwGroupList, kclient.InNamespace(gw.Namespace)); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing gateway groups: %w", err)
+	}
+	for _, gwGroup := range gwGroupList.Items {
+		if !inGwGroups[gwGroup.Name] {
+			continue
+		}
+
+		gwGroup.Default()
+		gwGroups[gwGroup.Name] = gwintapi.GatewayGroupInfo{
+			Redundancy: gwGroup.Spec.Redundancy,
+		}
+		gwGroupComms[gwGroup.Name] = gwGroup.Status.Communities
+	}
 	gws := &gwapi.GatewayList{}
 	if err := r.List(ctx, gws); err != nil {
 		return kctrl.Result{}, fmt.Errorf("listing gateways: %w", err)
 	}
+	// gateways expected to apply the peerings of each group
+	gwGroupMembers := map[string][]string{}
 	for _, gw := range gws.Items {
 		for _, gr := range gw.Spec.Groups {
+			if gw.DeletionTimestamp == nil {
+				gwGroupMembers[gr.Name] = append(gwGroupMembers[gr.Name], gw.Name)
+			}
+
 			if !inGwGroups[gr.Name] {
 				continue
 			}
 
 			info := gwGroups[gr.Name]
-			info.Members = append(info.Members, gwintapi.GatewayGroupMember{
+			if info.Redundancy.Mode == "" {
+				// group isn't created yet, e.g. the default one
+				info.Redundancy.Default()
+			}
+			member := gwintapi.GatewayGroupMember{
 				Name:     gw.Name,
 				Priority: gr.Priority,
 				VTEPIP:   gw.Spec.VTEPIP,
-			})
+			}
+			if info.Redundancy.Mode == gwapi.GatewayGroupModeECMP {
+				member.Weight = max(gr.Weight, 1)
+			}
+			// allocated by the gateway group controller, gateways are reconciled again on group status changes
+			if id, exists := gwGroupComms[gr.Name][gw.Name]; exists {
+				member.Community = cfg.Communities[id]
+			}
+			info.Members = append(info.Members, member)
 			gwGroups[gr.Name] = info
 		}
 	}
@@ -206,10 +470,16 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 	vpcs := map[string]gwintapi.VPCInfoData{}
 	for _, vpc := range vpcList.Items {
 		if !vpc.IsReady() {
-			l.Info("VPCInfo not ready, retrying")
+			// only reported once per VPCInfo, VPCInfo changes are watched so retries are just a fallback
+			changed, delay := r.vpcWaits.wait(gw.Name, vpc.Name)
+			l.Info("VPCInfo not ready, retrying", "vpc", vpc.Name, "after", delay)
+			if changed {
+				r.recorder.Eventf(gw, &vpc, corev1.EventTypeNormal, EventReasonWaitingForVPCInfo, "Reconcile",
+					"VPCInfo %s is not ready yet", vpc.Name)
+			}
 
 			// TODO consider ignoring non-ready VPCs
-			return kctrl.Result{Requeue: true, RequeueAfter: 1 * time.Second}, nil
+			return kctrl.Result{RequeueAfter: delay}, nil
 		}
 		vpcs[vpc.Name] = gwintapi.VPCInfoData{
 			VPCInfoSpec:   vpc.Spec,
@@ -217,12 +487,27 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 		}
 	}
 
+	r.vpcWaits.done(gw.Name)
+
 	peeringList := &gwapi.PeeringList{}
 	if err := r.List(ctx, peeringList); err != nil {
 		return kctrl.Result{}, fmt.Errorf("listing peerings: %w", err)
 	}
 	peerings := map[string]gwapi.PeeringSpec{}
+	peeringTargets := map[string]peeringTarget{}
 	for _, peering := range peeringList.Items {
+		// withdraw the peerings being deleted, they are kept by the finalizer until it's applied
+		if peering.DeletionTimestamp != nil {
+			continue
+		}
+
+		gwGroup := peering.AssignedGatewayGroup()
+		if gwGroup == "" {
+			l.Info("Peering isn't placed to a gateway group yet, skipping", "peering", peering.Name, "ns", peering.Namespace)
+
+			continue
+		}
+
 		missingVPC := false
 
 		for peerVPC := range peering.Spec.Peering {
@@ -239,19 +524,33 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 			continue
 		}
 
-		peerings[peering.Name] = peering.Spec
+		// agents only know the actual groups so the auto one is replaced with the group picked by the controller
+		spec := peering.Spec
+		spec.GatewayGroup = gwGroup
+		peerings[peering.Name] = spec
+		peeringTargets[peering.Name] = peeringTarget{gen: peering.Generation, gws: gwGroupMembers[gwGroup]}
 	}
 
 	comms := map[string]string{}
-	for id, comm := range r.cfg.Communities {
+	for id, comm := range cfg.Communities {
 		comms[strconv.FormatUint(uint64(id), 10)] = comm
 	}
 
 	// we intentionally manage gateway agent in the default namespace
+	gwAg := &gwintapi.GatewayAgent{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: gw.Name}}
 	{
-		gwAg := &gwintapi.GatewayAgent{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: gw.Name}}
+		placement, prevPlacement := gatewayPlacement(gw), ""
 		if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, gwAg, func() error {
-			// TODO consider blocking owner deletion, would require foregroundDeletion finalizer on the owner
+			if err := ctrlutil.SetControllerReference(gw, gwAg, r.Scheme()); err != nil {
+				return fmt.Errorf("setting gateway agent owner: %w", err)
+			}
+			setGatewayLabel(gwAg, gw.Name)
+
+			prevPlacement = gwAg.Annotations[gatewayPlacementAnnotation]
+			if gwAg.Annotations == nil {
+				gwAg.Annotations = map[string]string{}
+			}
+			gwAg.Annotations[gatewayPlacementAnnotation] = placement
 
 			gwAg.Spec.AgentVersion = ""
 			gwAg.Spec.Gateway = gw.Spec
@@ -260,20 +559,154 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 			gwAg.Spec.Groups = gwGroups
 			gwAg.Spec.Communities = comms
 			gwAg.Spec.Config = gwintapi.GatewayAgentSpecConfig{
-				FabricBFD: r.cfg.FabricBFD,
+				FabricBFD:  cfg.FabricBFD,
+				BGPAuthDir: bgpAuthMountPath,
+			}
+			if gw.Spec.Drain.Enabled && gw.Spec.Drain.Mode == gwapi.GatewayDrainModeCommunity {
+				gwAg.Spec.Config.DrainCommunity = cfg.DrainCommunity
+				if gwAg.Spec.Config.DrainCommunity == "" {
+					gwAg.Spec.Config.DrainCommunity = meta.DefaultDrainCommunity
+				}
 			}
 
 			return nil
 		}); err != nil {
+			r.recorder.Eventf(gw, nil, corev1.EventTypeWarning, EventReasonAgentUpdateFailed, "Reconcile",
+				"Failed to update gateway agent: %s", err.Error())
+
 			return kctrl.Result{}, fmt.Errorf("creating or updating gateway agent: %w", err)
 		}
+
+		if spec, err := json.Marshal(gwAg.Spec); err == nil {
+			gatewayAgentSpecSize.WithLabelValues(gw.Name).Set(float64(len(spec)))
+		}
+
+		// status reported from the previous node is stale, so reset it to wait for the agent on the new node
+		if prevPlacement != "" && prevPlacement != placement {
+			l.Info("Gateway moved to another node, resetting agent status", "from", prevPlacement, "to", placement)
+			r.recorder.Eventf(gw, nil, corev1.EventTypeNormal, EventReasonMoved, "Reconcile",
+				"Gateway moved from %s to %s", prevPlacement, placement)
+
+			gwAg.Status = gwintapi.GatewayAgentStatus{}
+			if err := r.Status().Update(ctx, gwAg); err != nil {
+				return kctrl.Result{}, fmt.Errorf("resetting gateway agent status: %w", err)
+			}
+		}
 	}
 
-	if err := r.deployGateway(ctx, gw); err != nil {
+	{
+		now := time.Now()
+		r.propagation.sent(peeringTargets, gw.Name, gwAg.Generation, gwAg.Status.LastAppliedGen, now)
+		r.propagation.applied(gw.Name, gwAg.Status.LastAppliedGen, now)
+	}
+
+	images, err := r.gatewayImages(ctx, cfg, gw)
+	if err != nil {
+		return kctrl.Result{}, fmt.Errorf("getting gateway images: %w", err)
+	}
+
+	if err := r.deployGateway(ctx, cfg, gw, images); err != nil {
+		r.recorder.Eventf(gw, nil, corev1.EventTypeWarning, EventReasonDeployFailed, "Deploy",
+			"Failed to deploy gateway: %s", err.Error())
+
 		return kctrl.Result{}, fmt.Errorf("deploying gateway: %w", err)
 	}
 
-	return kctrl.Result{}, nil
+	running, rolledOut, err := r.runningImages(ctx, cfg, gw)
+	if err != nil {
+		return kctrl.Result{}, fmt.Errorf("getting gateway running images: %w", err)
+	}
+	imagesChanged := rolledOut && gw.Status.Images != running
+	if imagesChanged {
+		gw.Status.Images = running
+	}
+
+	wasReady := kmeta.IsStatusConditionTrue(gw.Status.Conditions, gwapi.GatewayConditionReady)
+	if setGatewayStatus(gw, gwAg, time.Now()) || imagesChanged {
+		if err := r.Status().Update(ctx, gw); err != nil {
+			return kctrl.Result{}, fmt.Errorf("updating gateway status: %w", err)
+		}
+
+		if ready := kmeta.FindStatusCondition(gw.Status.Conditions, gwapi.GatewayConditionReady); ready != nil {
+			switch {
+			case !wasReady && ready.Status == kmetav1.ConditionTrue:
+				r.recorder.Eventf(gw, nil, corev1.EventTypeNormal, EventReasonReady, "Status", "%s", ready.Message)
+			case wasReady && ready.Status != kmetav1.ConditionTrue:
+				r.recorder.Eventf(gw, nil, corev1.EventTypeWarning, EventReasonNotReady, "Status", "%s", ready.Message)
+			}
+		}
+	}
+
+	// requeue to catch the agent heartbeat going stale
+	return kctrl.Result{RequeueAfter: AgentHeartbeatTimeout}, nil
+}
+
+// gatewayPlacement returns a string representation of the gateway node placement to detect gateway moving between nodes
+func gatewayPlacement(gw *gwapi.Gateway) string {
+	if len(gw.Spec.NodeSelector) > 0 {
+		return "selector:" + labels.SelectorFromSet(gw.Spec.NodeSelector).String()
+	}
+
+	return "node:" + gw.Node()
+}
+
+// gatewayNodeAffinity returns the node selector and affinity to place gateway pods on the gateway node
+func gatewayNodeAffinity(gw *gwapi.Gateway) (map[string]string, *corev1.Affinity) {
+	switch {
+	case len(gw.Spec.NodeSelector) > 0:
+		return gw.Spec.NodeSelector, nil
+	case gw.Spec.NodeName != "":
+		// same as the DaemonSet controller uses to place pods on a specific node
+		return nil, &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchFields: []corev1.NodeSelectorRequirement{
+								{
+									Key:      "metadata.name",
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{gw.Spec.NodeName},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	default:
+		// kept as is to not restart gateways that rely on the gateway name being equal to the node hostname
+		return map[string]string{"kubernetes.io/hostname": gw.Name}, nil
+	}
+}
+
+func setGatewayLabel(obj kclient.Object, gwName string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[gatewayLabel] = gwName
+	obj.SetLabels(labels)
+}
+
+// cleanupGateway deletes all per-gateway resources, including the ones in the gateway namespace which aren't
+// garbage collected as owner references can't be used across namespaces
+func (r *GatewayReconciler) cleanupGateway(ctx context.Context, cfg *meta.GatewayCtrlConfig, gw *gwapi.Gateway) error {
+	for _, obj := range []kclient.Object{
+		&appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: entityName(gw.Name, "dataplane")}},
+		&appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: entityName(gw.Name, "frr")}},
+		&corev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: entityName(gw.Name, "bgp-auth")}},
+		&corev1.ServiceAccount{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: entityName(gw.Name)}},
+		&rbacv1.RoleBinding{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: entityName(gw.Name)}},
+		&rbacv1.Role{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: entityName(gw.Name)}},
+		&gwintapi.GatewayAgent{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: gw.Name}},
+	} {
+		if err := r.Delete(ctx, obj); kclient.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("deleting %T %s/%s: %w", obj, obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+
+	return nil
 }
 
 func entityName(gwName string, t ...string) string {
@@ -284,15 +717,96 @@ func entityName(gwName string, t ...string) string {
 	return fmt.Sprintf("gw--%s--%s", gwName, strings.Join(t, "-"))
 }
 
-func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway) error {
+// bondSetupCommands returns shell commands to create a LACP bond if it doesn't exist, update its LACP settings if
+// they're changed and attach the members to it
+func bondSetupCommands(name string, bond *gwapi.GatewayInterfaceBond) string {
+	lacpActive := "on"
+	if bond.LACPMode == gwapi.GatewayBondLACPModePassive {
+		lacpActive = "off"
+	}
+
+	cmds := fmt.Sprintf("(ip l show dev %[1]s > /dev/null 2>&1 || ip l add %[1]s type bond mode 802.3ad lacp_active %[2]s lacp_rate %[3]s xmit_hash_policy %[4]s) && ",
+		name, lacpActive, bond.LACPRate, bond.HashPolicy)
+	// LACP settings of an existing bond could only be changed while it's down, it's brought up with the other interfaces
+	cmds += fmt.Sprintf("([[ \"$(cut -d' ' -f1 /sys/class/net/%[1]s/bonding/lacp_active) $(cut -d' ' -f1 /sys/class/net/%[1]s/bonding/lacp_rate) $(cut -d' ' -f1 /sys/class/net/%[1]s/bonding/xmit_hash_policy)\" == \"%[2]s %[3]s %[4]s\" ]] || (ip l set dev %[1]s down && ip l set dev %[1]s type bond lacp_active %[2]s lacp_rate %[3]s xmit_hash_policy %[4]s)) && ",
+		name, lacpActive, bond.LACPRate, bond.HashPolicy)
+	for _, member := range bond.Members {
+		cmds += fmt.Sprintf("([[ $(basename $(readlink -f \"/sys/class/net/%[1]s/master\")) == %[2]s ]] || (ip l set dev %[1]s down && ip l set dev %[1]s master %[2]s)) && ", member, name)
+	}
+
+	return cmds
+}
+
+// initIfacesScript returns the init container script creating the bonds and VLAN sub-interfaces and configuring all
+// gateway interfaces for the kernel driver
+func initIfacesScript(gw *gwapi.Gateway) string {
+	bondMembers := gw.Spec.BondMembers()
+	iArgs := "set -ex && "
+	for _, ifaceName := range slices.Sorted(maps.Keys(gw.Spec.Interfaces)) {
+		iface := gw.Spec.Interfaces[ifaceName]
+		if iface.Bond == nil {
+			continue
+		}
+
+		iArgs += bondSetupCommands(ifaceName, iface.Bond)
+	}
+	// parent interfaces have to be configured before VLAN sub-interfaces to have MTU set first
+	ifaceNames := slices.Sorted(maps.Keys(gw.Spec.Interfaces))
+	slices.SortStableFunc(ifaceNames, func(a, b string) int {
+		aVLAN, bVLAN := gw.Spec.Interfaces[a].VLAN != nil, gw.Spec.Interfaces[b].VLAN != nil
+		switch {
+		case aVLAN == bVLAN:
+			return 0
+		case bVLAN:
+			return -1
+		default:
+			return 1
+		}
+	})
+	for _, ifaceName := range ifaceNames {
+		iface := gw.Spec.Interfaces[ifaceName]
+		if iface.VLAN != nil {
+			iArgs += fmt.Sprintf("(ip l show dev %[1]s > /dev/null 2>&1 || ip l add link %[2]s name %[1]s type vlan id %[3]d) && ",
+				ifaceName, iface.VLAN.Parent, iface.VLAN.ID)
+		}
+		iArgs += fmt.Sprintf("(ethtool -K %s gro off || echo 'gro off failed') && ", ifaceName)
+		if _, isMember := bondMembers[ifaceName]; !isMember {
+			// bond members inherit MTU from the bond
+			iArgs += fmt.Sprintf("ip l set mtu %d dev %s && ", iface.MTU, ifaceName)
+		}
+		// slash separated key as interface names could contain dots, e.g. VLAN sub-interfaces
+		if slices.ContainsFunc(iface.IPs, isIPv6Prefix) {
+			iArgs += fmt.Sprintf("sysctl -w net/ipv6/conf/%s/disable_ipv6=0 && ", ifaceName)
+		}
+		iArgs += fmt.Sprintf("([[ $(basename $(readlink -f \"/sys/class/net/%[1]s/device/driver\")) == e1000 ]] && tee /sys/class/net/%[1]s/queues/rx-0/rps_cpus <<< ff || echo 'not e1000') && ", ifaceName)
+		iArgs += fmt.Sprintf("ip l set dev %s up && ", ifaceName)
+	}
+	iArgs += "date && echo done"
+
+	return iArgs
+}
+
+func isIPv6Prefix(in string) bool {
+	prefix, err := netip.ParsePrefix(in)
+
+	return err == nil && prefix.Addr().Is6()
+}
+
+func (r *GatewayReconciler) deployGateway(ctx context.Context, cfg *meta.GatewayCtrlConfig, gw *gwapi.Gateway, images gwapi.GatewayImages) error {
+	// all per-gateway resources are named after the gateway and not the node, so they're kept on re-homing
 	saName := entityName(gw.Name)
+	nodeSelector, affinity := gatewayNodeAffinity(gw)
 
 	{
 		sa := &corev1.ServiceAccount{ObjectMeta: kmetav1.ObjectMeta{
-			Namespace: r.cfg.Namespace,
+			Namespace: cfg.Namespace,
 			Name:      saName,
 		}}
-		if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, sa, func() error { return nil }); err != nil {
+		if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, sa, func() error {
+			setGatewayLabel(sa, gw.Name)
+
+			return nil
+		}); err != nil {
 			return fmt.Errorf("creating service account: %w", err)
 		}
 
@@ -301,6 +815,11 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 			Name:      saName,
 		}}
 		if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, role, func() error {
+			if err := ctrlutil.SetControllerReference(gw, role, r.Scheme()); err != nil {
+				return fmt.Errorf("setting role owner: %w", err)
+			}
+			setGatewayLabel(role, gw.Name)
+
 			role.Rules = []rbacv1.PolicyRule{
 				{
 					APIGroups:     []string{gwintapi.GroupVersion.Group},
@@ -326,6 +845,11 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 			Name:      saName,
 		}}
 		if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, roleBinding, func() error {
+			if err := ctrlutil.SetControllerReference(gw, roleBinding, r.Scheme()); err != nil {
+				return fmt.Errorf("setting role binding owner: %w", err)
+			}
+			setGatewayLabel(roleBinding, gw.Name)
+
 			roleBinding.Subjects = []rbacv1.Subject{
 				{
 					Kind:      "ServiceAccount",
@@ -345,6 +869,11 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 		}
 	}
 
+	bgpAuthHash, err := r.deployBGPAuthSecret(ctx, cfg, gw)
+	if err != nil {
+		return fmt.Errorf("deploying bgp auth secret: %w", err)
+	}
+
 	replaceUpdateStrategy := appv1.DaemonSetUpdateStrategy{
 		Type: appv1.RollingUpdateDaemonSetStrategyType,
 		RollingUpdate: &appv1.RollingUpdateDaemonSet{
@@ -375,25 +904,114 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 
 	{
 		args := []string{
+			"--name", gw.Name,
 			"--num-workers", fmt.Sprintf("%d", gw.Spec.Workers),
 			"--cli-sock-path", filepath.Join(dataplaneRunMountPath, "cli.sock"),
 			"--cpi-sock-path", filepath.Join(frrRunMountPath, cpiSocket),
 			"--frr-agent-path", filepath.Join(frrRunMountPath, frrAgentSocket),
-			"--metrics-address", fmt.Sprintf("127.0.0.1:%d", r.cfg.DataplaneMetricsPort),
+			"--metrics-address", fmt.Sprintf("127.0.0.1:%d", cfg.DataplaneMetricsPort),
 			"--bmp-enable",
 			"--bmp-address", "127.0.0.1:5000", // TODO: make it available via config
 			"--bmp-interval", "10000",
 		}
-		if gw.Spec.Profiling.Enabled {
-			// args = append(args, "--pyroscope-url", "http://alloy-gw.fab.svc.cluster.local:4040")
-			args = append(args, "--pyroscope-url", "http://localhost:4040")
+		if prof := gw.Spec.Profiling; prof.Enabled {
+			profURL := prof.URL
+			if profURL == "" {
+				profURL = cfg.ProfilingURL
+			}
+			if profURL == "" {
+				profURL = meta.DefaultProfilingURL
+			}
+			args = append(args,
+				"--pyroscope-url", profURL,
+				"--pyroscope-sample-rate", fmt.Sprintf("%d", prof.SampleRate),
+			)
+
+			tags := maps.Clone(prof.Tags)
+			if tags == nil {
+				tags = map[string]string{}
+			}
+			if _, exists := tags["gateway"]; !exists {
+				tags["gateway"] = gw.Name
+			}
+			for _, key := range slices.Sorted(maps.Keys(tags)) {
+				args = append(args, "--pyroscope-tag", key+"="+tags[key])
+			}
+			for _, profType := range prof.ProfileTypes {
+				args = append(args, "--pyroscope-profile-type", string(profType))
+			}
+		}
+
+		dpRes := gw.Spec.Resources
+		if dpRes.CPUSet != "" {
+			args = append(args, "--cpu-set", dpRes.CPUSet)
+		}
+		if dpRes.NUMANode != nil {
+			args = append(args, "--numa-node", fmt.Sprintf("%d", *dpRes.NUMANode))
+		}
+		if dpRes.Hugepages > 0 {
+			args = append(args, "--huge-dir", hugepagesMountPath)
+		}
+		resources, err := dataplaneResources(dpRes)
+		if err != nil {
+			return fmt.Errorf("building dataplane resources: %w", err)
+		}
+		dpVolumeMounts := []corev1.VolumeMount{
+			{
+				Name:      dataplaneRunVolumeName,
+				MountPath: dataplaneRunMountPath,
+			},
+			{
+				Name:      frrRunVolumeName,
+				MountPath: frrRunMountPath,
+			},
+			{
+				Name:      "dataplane-tmp",
+				MountPath: "/tmp",
+			},
+		}
+		dpVolumes := []corev1.Volume{
+			dataplaneSocketVolume,
+			frrSocketVolume,
+
+			{
+				Name: "dataplane-tmp",
+				VolumeSource: corev1.VolumeSource{
+					// TODO consider memory medium
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			},
+		}
+		if dpRes.Hugepages > 0 {
+			dpVolumeMounts = append(dpVolumeMounts, corev1.VolumeMount{
+				Name:      hugepagesVolumeName,
+				MountPath: hugepagesMountPath,
+			})
+			dpVolumes = append(dpVolumes, corev1.Volume{
+				Name: hugepagesVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						Medium: corev1.StorageMediumHugePagesPrefix + corev1.StorageMedium(dpRes.HugepageSize),
+					},
+				},
+			})
 		}
 
 		pcis, kernels := 0, 0
+		bondMembers := gw.Spec.BondMembers()
 		for _, ifaceName := range slices.Sorted(maps.Keys(gw.Spec.Interfaces)) {
+			if _, isMember := bondMembers[ifaceName]; isMember {
+				// bond members are only used through the bond
+				continue
+			}
+
 			iface := gw.Spec.Interfaces[ifaceName]
 			val := ifaceName
 			switch {
+			case iface.Bond != nil || iface.VLAN != nil:
+				// bonds and VLAN sub-interfaces are created in the kernel by the init container
+				kernels++
+				val += "=kernel@" + ifaceName
 			case iface.PCI != "":
 				pcis++
 				val += "=pci@" + iface.PCI
@@ -419,20 +1037,12 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 		// tmp hack to make dp work
 		var initContainers []corev1.Container
 		if driver == "kernel" {
-			iArgs := "set -ex && "
-			for _, ifaceName := range slices.Sorted(maps.Keys(gw.Spec.Interfaces)) {
-				iface := gw.Spec.Interfaces[ifaceName]
-				iArgs += fmt.Sprintf("(ethtool -K %s gro off || echo 'gro off failed') && ", ifaceName)
-				iArgs += fmt.Sprintf("ip l set mtu %d dev %s && ", iface.MTU, ifaceName)
-				iArgs += fmt.Sprintf("([[ $(basename $(readlink -f \"/sys/class/net/%[1]s/device/driver\")) == e1000 ]] && tee /sys/class/net/%[1]s/queues/rx-0/rps_cpus <<< ff || echo 'not e1000') && ", ifaceName)
-				iArgs += fmt.Sprintf("ip l set dev %s up && ", ifaceName)
-			}
-			iArgs += "date && echo done"
+			iArgs := initIfacesScript(gw)
 
 			initContainers = []corev1.Container{
 				{
 					Name:    "init-ifaces",
-					Image:   r.cfg.ToolboxRef,
+					Image:   images.Toolbox,
 					Command: []string{"/bin/bash", "-c", "--"},
 					Args:    []string{iArgs},
 					SecurityContext: &corev1.SecurityContext{
@@ -441,13 +1051,22 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 					},
 				},
 			}
+			if dpRes.CPUs > 0 {
+				// all containers need requests equal to limits for the pod to get Guaranteed QoS
+				initContainers[0].Resources = corev1.ResourceRequirements{
+					Requests: initContainerResources,
+					Limits:   initContainerResources,
+				}
+			}
 		}
 
 		dpDS := &appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{
-			Namespace: r.cfg.Namespace,
+			Namespace: cfg.Namespace,
 			Name:      entityName(gw.Name, "dataplane"),
 		}}
 		if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, dpDS, func() error {
+			setGatewayLabel(dpDS, gw.Name)
+
 			labels := map[string]string{
 				"app.kubernetes.io/name": dpDS.Name, // TODO
 			}
@@ -461,17 +1080,18 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 						Labels: labels,
 					},
 					Spec: corev1.PodSpec{
-						NodeSelector:                  map[string]string{"kubernetes.io/hostname": gw.Name},
+						NodeSelector:                  nodeSelector,
+						Affinity:                      affinity,
 						HostNetwork:                   true,
 						DNSPolicy:                     corev1.DNSClusterFirstWithHostNet,
 						TerminationGracePeriodSeconds: ptr.To(int64(10)),
-						Tolerations:                   r.cfg.Tolerations,
+						Tolerations:                   cfg.Tolerations,
 						ServiceAccountName:            saName,
 						InitContainers:                initContainers,
 						Containers: []corev1.Container{
 							{
 								Name:  "dataplane",
-								Image: r.cfg.DataplaneRef,
+								Image: images.Dataplane,
 								Args:  args,
 								SecurityContext: &corev1.SecurityContext{
 									Privileged: ptr.To(true),
@@ -483,34 +1103,11 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 										Value: "FULL",
 									},
 								},
-								VolumeMounts: []corev1.VolumeMount{
-									{
-										Name:      dataplaneRunVolumeName,
-										MountPath: dataplaneRunMountPath,
-									},
-									{
-										Name:      frrRunVolumeName,
-										MountPath: frrRunMountPath,
-									},
-									{
-										Name:      "dataplane-tmp",
-										MountPath: "/tmp",
-									},
-								},
-							},
-						},
-						Volumes: []corev1.Volume{
-							dataplaneSocketVolume,
-							frrSocketVolume,
-
-							{
-								Name: "dataplane-tmp",
-								VolumeSource: corev1.VolumeSource{
-									// TODO consider memory medium
-									EmptyDir: &corev1.EmptyDirVolumeSource{},
-								},
+								Resources:    resources,
+								VolumeMounts: dpVolumeMounts,
 							},
 						},
+						Volumes: dpVolumes,
 					},
 				},
 				UpdateStrategy: replaceUpdateStrategy,
@@ -539,10 +1136,12 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 
 	{
 		frrDS := &appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{
-			Namespace: r.cfg.Namespace,
+			Namespace: cfg.Namespace,
 			Name:      entityName(gw.Name, "frr"),
 		}}
 		if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, frrDS, func() error {
+			setGatewayLabel(frrDS, gw.Name)
+
 			labels := map[string]string{
 				"app.kubernetes.io/name": frrDS.Name, // TODO
 			}
@@ -554,18 +1153,23 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 				Template: corev1.PodTemplateSpec{
 					ObjectMeta: kmetav1.ObjectMeta{
 						Labels: labels,
+						Annotations: map[string]string{
+							// restart FRR to pick up the password changes
+							bgpAuthHashAnnotation: bgpAuthHash,
+						},
 					},
 					Spec: corev1.PodSpec{
-						NodeSelector:                  map[string]string{"kubernetes.io/hostname": gw.Name},
+						NodeSelector:                  nodeSelector,
+						Affinity:                      affinity,
 						HostNetwork:                   true,
 						DNSPolicy:                     corev1.DNSClusterFirstWithHostNet,
 						TerminationGracePeriodSeconds: ptr.To(int64(10)),
-						Tolerations:                   r.cfg.Tolerations,
+						Tolerations:                   cfg.Tolerations,
 						InitContainers: []corev1.Container{
 							// TODO remove it after frr container will take care of this
 							{
 								Name:    "init-frr",
-								Image:   r.cfg.FRRRef,
+								Image:   images.FRR,
 								Command: []string{"/bin/bash", "-c", "--"},
 								Args: []string{
 									"set -ex && " +
@@ -582,7 +1186,7 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 							// it's needed to avoid issues with leftover routes in the kernel being loaded by FRR on startup
 							{
 								Name:    "flush-zebra-nexthops",
-								Image:   r.cfg.FRRRef,
+								Image:   images.FRR,
 								Command: []string{"/bin/bash", "-c", "--"},
 								Args: []string{
 									"set -ex && " +
@@ -596,7 +1200,7 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 							// it's needed to avoid issues with leftover routes on the physical interface learned from BGP
 							{
 								Name:    "flush-vtepip",
-								Image:   r.cfg.FRRRef,
+								Image:   images.FRR,
 								Command: []string{"/bin/bash", "-c", "--"},
 								Args: []string{
 									"set -ex && " +
@@ -611,7 +1215,7 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 						Containers: []corev1.Container{
 							{
 								Name:    "frr",
-								Image:   r.cfg.FRRRef,
+								Image:   images.FRR,
 								Command: []string{"/bin/tini", "--"},
 								Args: []string{
 									"/libexec/frr/docker-start",
@@ -623,14 +1227,18 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 									Privileged: ptr.To(true),
 									RunAsUser:  ptr.To(int64(0)),
 								},
-								VolumeMounts: frrVolumeMounts,
+								VolumeMounts: append(slices.Clone(frrVolumeMounts), corev1.VolumeMount{
+									Name:      bgpAuthVolumeName,
+									MountPath: bgpAuthMountPath,
+									ReadOnly:  true,
+								}),
 							},
 							{
 								Name:    "frr-exporter",
-								Image:   r.cfg.FRRRef,
+								Image:   images.FRR,
 								Command: []string{"/bin/frr_exporter"},
 								Args: []string{
-									"--web.listen-address", fmt.Sprintf("127.0.0.1:%d", r.cfg.FRRMetricsPort),
+									"--web.listen-address", fmt.Sprintf("127.0.0.1:%d", cfg.FRRMetricsPort),
 									"--frr.socket.dir-path", frrRootRunMountPath,
 								},
 								SecurityContext: &corev1.SecurityContext{
@@ -663,6 +1271,15 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 									},
 								},
 							},
+							{
+								Name: bgpAuthVolumeName,
+								VolumeSource: corev1.VolumeSource{
+									Secret: &corev1.SecretVolumeSource{
+										SecretName:  entityName(gw.Name, "bgp-auth"),
+										DefaultMode: ptr.To(int32(0o400)),
+									},
+								},
+							},
 						},
 					},
 				},
@@ -677,3 +1294,88 @@ func (r *GatewayReconciler) deployGateway(ctx context.Context, gw *gwapi.Gateway
 
 	return nil
 }
+
+// deployBGPAuthSecret collects BGP neighbor passwords from the secrets referenced in the gateway namespace into a
+// per-gateway secret in the gateway pods namespace and returns a hash of its content
+func (r *GatewayReconciler) deployBGPAuthSecret(ctx context.Context, cfg *meta.GatewayCtrlConfig, gw *gwapi.Gateway) (string, error) {
+	data := map[string][]byte{}
+	for _, neigh := range gw.Spec.Neighbors {
+		if neigh.Auth == nil {
+			continue
+		}
+
+		ref := neigh.Auth.SecretRef
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, kclient.ObjectKey{Namespace: gw.Namespace, Name: ref.Name}, secret); err != nil {
+			return "", fmt.Errorf("getting secret %s for BGP neighbor %s: %w", ref.Name, neigh.IP, err)
+		}
+
+		password, ok := secret.Data[ref.Key]
+		if !ok || len(password) == 0 {
+			return "", fmt.Errorf("secret %s has no key %s for BGP neighbor %s", ref.Name, ref.Key, neigh.IP) //nolint:err113
+		}
+
+		data[gwintapi.BGPNeighborAuthKey(neigh.IP)] = password
+	}
+
+	authSecret := &corev1.Secret{ObjectMeta: kmetav1.ObjectMeta{
+		Namespace: cfg.Namespace,
+		Name:      entityName(gw.Name, "bgp-auth"),
+	}}
+	if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, authSecret, func() error {
+		setGatewayLabel(authSecret, gw.Name)
+		authSecret.Type = corev1.SecretTypeOpaque
+		authSecret.Data = data
+
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("creating or updating bgp auth secret: %w", err)
+	}
+
+	hash := sha256.New()
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(data[key])
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+var initContainerResources = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("100m"),
+	corev1.ResourceMemory: resource.MustParse("128Mi"),
+}
+
+// dataplaneResources builds the dataplane container resources with requests equal to limits, so the pod gets the
+// Guaranteed QoS class if CPUs are set
+func dataplaneResources(res gwapi.GatewayDataplaneResources) (corev1.ResourceRequirements, error) {
+	list := corev1.ResourceList{}
+	if res.CPUs > 0 {
+		list[corev1.ResourceCPU] = *resource.NewQuantity(int64(res.CPUs), resource.DecimalSI)
+	}
+	if res.Memory != "" {
+		mem, err := resource.ParseQuantity(res.Memory)
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("parsing memory %q: %w", res.Memory, err)
+		}
+		list[corev1.ResourceMemory] = mem
+	}
+	if res.Hugepages > 0 {
+		size, err := resource.ParseQuantity(string(res.HugepageSize))
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("parsing hugepage size %q: %w", res.HugepageSize, err)
+		}
+		list[corev1.ResourceHugePagesPrefix+corev1.ResourceName(res.HugepageSize)] = *resource.NewQuantity(
+			size.Value()*int64(res.Hugepages), resource.BinarySI)
+	}
+	if len(list) == 0 {
+		return corev1.ResourceRequirements{}, nil
+	}
+
+	return corev1.ResourceRequirements{
+		Requests: list,
+		Limits:   list.DeepCopy(),
+	}, nil
+}
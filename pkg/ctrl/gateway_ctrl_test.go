@@ -0,0 +1,159 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	"go.githedgehog.com/gateway/api/meta"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestCleanupGateway(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, gwapi.AddToScheme(scheme))
+	require.NoError(t, gwintapi.AddToScheme(scheme))
+
+	cfg := &meta.GatewayCtrlConfig{Namespace: "fab"}
+	gw := &gwapi.Gateway{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gw-1"}}
+
+	objs := []kclient.Object{
+		&appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: "gw--gw-1--dataplane"}},
+		&appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: "gw--gw-1--frr"}},
+		&corev1.ServiceAccount{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: "gw-gw-1"}},
+		&rbacv1.Role{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gw-gw-1"}},
+		&rbacv1.RoleBinding{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gw-gw-1"}},
+		&gwintapi.GatewayAgent{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gw-1"}},
+	}
+	// resources of another gateway should be kept
+	other := &appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{Namespace: cfg.Namespace, Name: "gw--gw-2--dataplane"}}
+
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, other)...).Build()
+	r := &GatewayReconciler{Client: kube}
+
+	// bgp auth secret is missing which should be ignored
+	require.NoError(t, r.cleanupGateway(t.Context(), cfg, gw))
+
+	for _, obj := range objs {
+		err := kube.Get(t.Context(), kclient.ObjectKeyFromObject(obj), obj)
+		require.True(t, kapierrors.IsNotFound(err), "%T %s should be deleted", obj, obj.GetName())
+	}
+	require.NoError(t, kube.Get(t.Context(), kclient.ObjectKeyFromObject(other), other))
+}
+
+func TestEnqueueGatewayByLabel(t *testing.T) {
+	ds := &appv1.DaemonSet{ObjectMeta: kmetav1.ObjectMeta{Namespace: "fab", Name: "gw--gw-1--dataplane"}}
+	require.Empty(t, enqueueGatewayByLabel(t.Context(), ds))
+
+	setGatewayLabel(ds, "gw-1")
+	res := enqueueGatewayByLabel(t.Context(), ds)
+	require.Len(t, res, 1)
+	require.Equal(t, kmetav1.NamespaceDefault, res[0].Namespace)
+	require.Equal(t, "gw-1", res[0].Name)
+}
+
+func TestGatewayConfigInputChanged(t *testing.T) {
+	peering := func(f ...func(p *gwapi.Peering)) *gwapi.Peering {
+		p := &gwapi.Peering{ObjectMeta: kmetav1.ObjectMeta{Name: "vpc1--vpc2", Generation: 1}}
+		for _, fn := range f {
+			fn(p)
+		}
+
+		return p
+	}
+	group := func(f ...func(gg *gwapi.GatewayGroup)) *gwapi.GatewayGroup {
+		gg := &gwapi.GatewayGroup{
+			ObjectMeta: kmetav1.ObjectMeta{Name: "gr1", Generation: 1},
+			Status:     gwapi.GatewayGroupStatus{Communities: map[string]uint32{"gw-1": 0}},
+		}
+		for _, fn := range f {
+			fn(gg)
+		}
+
+		return gg
+	}
+
+	for _, tt := range []struct {
+		name     string
+		old, new kclient.Object
+		expected bool
+	}{
+		{
+			name: "peering-traffic",
+			old:  peering(),
+			new: peering(func(p *gwapi.Peering) {
+				p.Status.Traffic = map[string]gwapi.PeeringTraffic{"vpc1": {Packets: 10}}
+				p.Status.Propagation = map[string]gwapi.PeeringPropagation{"gw-1": {Generation: 1, AgentGeneration: 2}}
+			}),
+		},
+		{
+			name:     "peering-spec",
+			old:      peering(),
+			new:      peering(func(p *gwapi.Peering) { p.Generation = 2 }),
+			expected: true,
+		},
+		{
+			name:     "peering-placed",
+			old:      peering(),
+			new:      peering(func(p *gwapi.Peering) { p.Status.GatewayGroup = "gr1" }),
+			expected: true,
+		},
+		{
+			name:     "peering-deleted",
+			old:      peering(),
+			new:      peering(func(p *gwapi.Peering) { p.DeletionTimestamp = &kmetav1.Time{} }),
+			expected: true,
+		},
+		{
+			name: "group-health",
+			old:  group(),
+			new:  group(func(gg *gwapi.GatewayGroup) { gg.Status.Healthy = 1; gg.Status.Active = []string{"gw-1"} }),
+		},
+		{
+			name: "group-communities",
+			old:  group(),
+			new: group(func(gg *gwapi.GatewayGroup) {
+				gg.Status.Communities = map[string]uint32{"gw-1": 0, "gw-2": 1}
+			}),
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, gatewayConfigInputChanged(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new}))
+		})
+	}
+}
+
+func TestInitIfacesScriptVLAN(t *testing.T) {
+	script := initIfacesScript(&gwapi.Gateway{Spec: gwapi.GatewaySpec{
+		Interfaces: map[string]gwapi.GatewayInterface{
+			"eth0": {Kernel: "eth0", MTU: 9036},
+			"eth0.100": {
+				VLAN: &gwapi.GatewayInterfaceVLAN{Parent: "eth0", ID: 100},
+				IPs:  []string{"172.30.100.1/31", "fd00::1/127"},
+				MTU:  9000,
+			},
+		},
+	}})
+
+	require.Contains(t, script, "ip l add link eth0 name eth0.100 type vlan id 100")
+	require.Contains(t, script, "sysctl -w net/ipv6/conf/eth0.100/disable_ipv6=0 && ")
+	require.NotContains(t, script, "net.ipv6.conf.")
+	require.Less(t, strings.Index(script, "ip l set mtu 9036 dev eth0 "), strings.Index(script, "ip l set mtu 9000 dev eth0.100 "),
+		"parent should be configured before the sub-interface")
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"fmt"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/api/meta"
+	appv1 "k8s.io/api/apps/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// gatewayImages returns the images the gateway should run: controller config defaults overridden by the first group
+// of the gateway with images set and then by the gateway itself. Group images are held back for all gateways except
+// the canary until the canary is ready and running them.
+func (r *GatewayReconciler) gatewayImages(ctx context.Context, cfg *meta.GatewayCtrlConfig, gw *gwapi.Gateway) (gwapi.GatewayImages, error) {
+	images := gwapi.GatewayImages{
+		Dataplane: cfg.DataplaneRef,
+		FRR:       cfg.FRRRef,
+		Toolbox:   cfg.ToolboxRef,
+	}
+
+	for _, membership := range gw.Spec.Groups {
+		gwGroup := &gwapi.GatewayGroup{}
+		if err := r.Get(ctx, kclient.ObjectKey{Namespace: gw.Namespace, Name: membership.Name}, gwGroup); err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+
+			return images, fmt.Errorf("getting gateway group %s: %w", membership.Name, err)
+		}
+		if gwGroup.Spec.Images.IsEmpty() {
+			continue
+		}
+
+		target := images.Override(gwGroup.Spec.Images)
+		if gwGroup.Spec.Canary != "" && gwGroup.Spec.Canary != gw.Name && !gw.Status.Images.IsEmpty() {
+			canaryReady, err := r.canaryReady(ctx, gw.Namespace, gwGroup.Spec.Canary, images, gwGroup)
+			if err != nil {
+				return images, err
+			}
+			if !canaryReady {
+				kctrllog.FromContext(ctx).Info("Holding gateway group images until canary is ready", "group", gwGroup.Name, "canary", gwGroup.Spec.Canary)
+
+				// keep whatever the gateway is running now
+				target = images.Override(gw.Status.Images)
+			}
+		}
+		images = target
+
+		// only the first group with images is used
+		break
+	}
+
+	return images.Override(gw.Spec.Images), nil
+}
+
+// canaryReady returns true if the canary gateway is ready and running the group images (dataplane and FRR)
+func (r *GatewayReconciler) canaryReady(ctx context.Context, ns, name string, base gwapi.GatewayImages, gwGroup *gwapi.GatewayGroup) (bool, error) {
+	canary := &gwapi.Gateway{}
+	if err := r.Get(ctx, kclient.ObjectKey{Namespace: ns, Name: name}, canary); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("getting canary gateway %s: %w", name, err)
+	}
+
+	expected := base.Override(gwGroup.Spec.Images).Override(canary.Spec.Images)
+	running := canary.Status.Images
+
+	return kmeta.IsStatusConditionTrue(canary.Status.Conditions, gwapi.GatewayConditionReady) &&
+		running.Dataplane == expected.Dataplane && running.FRR == expected.FRR, nil
+}
+
+// runningImages returns the images from the gateway daemonsets and true if both of them are completely rolled out,
+// missing daemonsets (e.g. not yet visible in the cache) are reported as nothing running
+func (r *GatewayReconciler) runningImages(ctx context.Context, cfg *meta.GatewayCtrlConfig, gw *gwapi.Gateway) (gwapi.GatewayImages, bool, error) {
+	images := gwapi.GatewayImages{}
+
+	dpDS := &appv1.DaemonSet{}
+	if err := r.Get(ctx, kclient.ObjectKey{Namespace: cfg.Namespace, Name: entityName(gw.Name, "dataplane")}, dpDS); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return images, false, nil
+		}
+
+		return images, false, fmt.Errorf("getting dataplane daemonset: %w", err)
+	}
+	frrDS := &appv1.DaemonSet{}
+	if err := r.Get(ctx, kclient.ObjectKey{Namespace: cfg.Namespace, Name: entityName(gw.Name, "frr")}, frrDS); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return images, false, nil
+		}
+
+		return images, false, fmt.Errorf("getting frr daemonset: %w", err)
+	}
+
+	for _, container := range dpDS.Spec.Template.Spec.InitContainers {
+		if container.Name == "init-ifaces" {
+			images.Toolbox = container.Image
+		}
+	}
+	for _, container := range dpDS.Spec.Template.Spec.Containers {
+		if container.Name == "dataplane" {
+			images.Dataplane = container.Image
+		}
+	}
+	for _, container := range frrDS.Spec.Template.Spec.Containers {
+		if container.Name == "frr" {
+			images.FRR = container.Image
+		}
+	}
+
+	return images, daemonSetRolledOut(dpDS) && daemonSetRolledOut(frrDS), nil
+}
+
+func daemonSetRolledOut(ds *appv1.DaemonSet) bool {
+	return ds.Status.ObservedGeneration >= ds.Generation &&
+		ds.Status.DesiredNumberScheduled > 0 &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberUnavailable == 0
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	"go.githedgehog.com/gateway/api/meta"
+	appv1 "k8s.io/api/apps/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestGatewayImages(t *testing.T) {
+	cfg := &meta.GatewayCtrlConfig{
+		DataplaneRef: "registry.local:31000/githedgehog/dataplane:v1",
+		FRRRef:       "registry.local:31000/githedgehog/dpdk-sys/frr:v1",
+		ToolboxRef:   "registry.local:31000/githedgehog/toolbox:v1",
+	}
+	v1 := gwapi.GatewayImages{Dataplane: cfg.DataplaneRef, FRR: cfg.FRRRef, Toolbox: cfg.ToolboxRef}
+	v2 := v1.Override(gwapi.GatewayImages{Dataplane: "registry.local:31000/githedgehog/dataplane:v2"})
+
+	group := func(canary string) *gwapi.GatewayGroup {
+		return &gwapi.GatewayGroup{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gr1"},
+			Spec: gwapi.GatewayGroupSpec{
+				Images: gwapi.GatewayImages{Dataplane: v2.Dataplane},
+				Canary: canary,
+			},
+		}
+	}
+	gateway := func(name string, running gwapi.GatewayImages, ready bool) *gwapi.Gateway {
+		gw := &gwapi.Gateway{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: name},
+			Spec: gwapi.GatewaySpec{
+				Groups: []gwapi.GatewayGroupMembership{{Name: "gr1"}},
+			},
+			Status: gwapi.GatewayStatus{Images: running},
+		}
+		status := kmetav1.ConditionFalse
+		if ready {
+			status = kmetav1.ConditionTrue
+		}
+		gw.Status.Conditions = []kmetav1.Condition{{Type: gwapi.GatewayConditionReady, Status: status}}
+
+		return gw
+	}
+
+	for _, tt := range []struct {
+		name     string
+		gw       *gwapi.Gateway
+		objs     []kclient.Object
+		expected gwapi.GatewayImages
+	}{
+		{
+			name:     "no-group",
+			gw:       gateway("gw-1", v1, true),
+			expected: v1,
+		},
+		{
+			name:     "group-without-canary",
+			gw:       gateway("gw-1", v1, true),
+			objs:     []kclient.Object{group("")},
+			expected: v2,
+		},
+		{
+			name:     "canary-itself",
+			gw:       gateway("gw-1", v1, true),
+			objs:     []kclient.Object{group("gw-1")},
+			expected: v2,
+		},
+		{
+			name:     "canary-not-ready",
+			gw:       gateway("gw-2", v1, true),
+			objs:     []kclient.Object{group("gw-1"), gateway("gw-1", v2, false)},
+			expected: v1,
+		},
+		{
+			name:     "canary-not-rolled-out",
+			gw:       gateway("gw-2", v1, true),
+			objs:     []kclient.Object{group("gw-1"), gateway("gw-1", v1, true)},
+			expected: v1,
+		},
+		{
+			name:     "canary-ready",
+			gw:       gateway("gw-2", v1, true),
+			objs:     []kclient.Object{group("gw-1"), gateway("gw-1", v2, true)},
+			expected: v2,
+		},
+		{
+			name:     "new-gateway-not-held",
+			gw:       gateway("gw-2", gwapi.GatewayImages{}, false),
+			objs:     []kclient.Object{group("gw-1"), gateway("gw-1", v2, false)},
+			expected: v2,
+		},
+		{
+			name: "gateway-override",
+			gw: func() *gwapi.Gateway {
+				gw := gateway("gw-2", v1, true)
+				gw.Spec.Images.FRR = "registry.local:31000/githedgehog/dpdk-sys/frr:v3"
+
+				return gw
+			}(),
+			objs:     []kclient.Object{group("")},
+			expected: v2.Override(gwapi.GatewayImages{FRR: "registry.local:31000/githedgehog/dpdk-sys/frr:v3"}),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, gwapi.AddToScheme(scheme))
+
+			r := &GatewayReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objs...).Build(),
+			}
+
+			images, err := r.gatewayImages(t.Context(), cfg, tt.gw)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, images)
+		})
+	}
+}
+
+func TestEnqueueGatewaysForCanary(t *testing.T) {
+	gateway := func(name string, groups ...string) *gwapi.Gateway {
+		gw := &gwapi.Gateway{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: name}}
+		for _, group := range groups {
+			gw.Spec.Groups = append(gw.Spec.Groups, gwapi.GatewayGroupMembership{Name: group})
+		}
+
+		return gw
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwapi.AddToScheme(scheme))
+
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&gwapi.GatewayGroup{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gr1"},
+				Spec:       gwapi.GatewayGroupSpec{Canary: "gw-1"},
+			},
+			&gwapi.GatewayGroup{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gr2"},
+			},
+			gateway("gw-1", "gr1", "gr2"),
+			gateway("gw-2", "gr1"),
+			gateway("gw-3", "gr2"),
+		).Build(),
+	}
+
+	require.Equal(t, []reconcile.Request{
+		{NamespacedName: ktypes.NamespacedName{Namespace: kmetav1.NamespaceDefault, Name: "gw-2"}},
+	}, r.enqueueGatewaysForCanary(t.Context(), gateway("gw-1")))
+	require.Empty(t, r.enqueueGatewaysForCanary(t.Context(), gateway("gw-3")))
+}
+
+func TestRunningImagesMissingDaemonSets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwapi.AddToScheme(scheme))
+	require.NoError(t, appv1.AddToScheme(scheme))
+
+	r := &GatewayReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	images, rolledOut, err := r.runningImages(t.Context(), &meta.GatewayCtrlConfig{Namespace: "fab"}, &gwapi.Gateway{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: "gw-1"},
+	})
+	require.NoError(t, err)
+	require.False(t, rolledOut)
+	require.True(t, images.IsEmpty())
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// AgentHeartbeatTimeout is the max age of the last agent heartbeat for the agent to be considered alive
+const AgentHeartbeatTimeout = 2 * time.Minute
+
+// agentStatusChanged returns true if any of the gateway agent fields mirrored into the gateway status are changed,
+// plain heartbeats and counters are ignored unless the agent is back after missing heartbeats
+func agentStatusChanged(evt event.UpdateEvent) bool {
+	if agentProgressChanged(evt) {
+		return true
+	}
+
+	oldAg, ok := evt.ObjectOld.(*gwintapi.GatewayAgent)
+	if !ok {
+		return true
+	}
+	newAg, ok := evt.ObjectNew.(*gwintapi.GatewayAgent)
+	if !ok {
+		return true
+	}
+
+	return !oldAg.Status.LastAppliedTime.Equal(&newAg.Status.LastAppliedTime) ||
+		oldAg.Status.AgentVersion != newAg.Status.AgentVersion ||
+		oldAg.Status.State.Dataplane.Version != newAg.Status.State.Dataplane.Version ||
+		newAg.Status.LastHeartbeat.Sub(oldAg.Status.LastHeartbeat.Time) > AgentHeartbeatTimeout ||
+		!maps.Equal(enabledBGPSessions(oldAg), enabledBGPSessions(newAg))
+}
+
+// enabledBGPSessions returns the session states of the enabled BGP neighbors keyed by vrf/neighbor
+func enabledBGPSessions(gwAg *gwintapi.GatewayAgent) map[string]gwintapi.BGPNeighborSessionState {
+	res := map[string]gwintapi.BGPNeighborSessionState{}
+	for vrfName, vrf := range gwAg.Status.State.BGP.VRFs {
+		for neighIP, neigh := range vrf.Neighbors {
+			if neigh.Enabled {
+				res[vrfName+"/"+neighIP] = neigh.SessionState
+			}
+		}
+	}
+
+	return res
+}
+
+// setGatewayStatus mirrors the gateway agent status into the gateway status and conditions, it returns true if the
+// status has been changed
+func setGatewayStatus(gw *gwapi.Gateway, gwAg *gwintapi.GatewayAgent, now time.Time) bool {
+	orig := gw.Status.DeepCopy()
+	status := &gw.Status
+
+	status.LastHeartbeat = gwAg.Status.LastHeartbeat
+	status.LastAppliedTime = gwAg.Status.LastAppliedTime
+	status.LastAppliedGen = gwAg.Status.LastAppliedGen
+	status.DesiredGen = gwAg.Generation
+	status.AgentVersion = gwAg.Status.AgentVersion
+	status.DataplaneVersion = gwAg.Status.State.Dataplane.Version
+
+	notEstablished := []string{}
+	status.BGP = gwapi.GatewayBGPSummary{}
+	for _, vrfName := range slices.Sorted(maps.Keys(gwAg.Status.State.BGP.VRFs)) {
+		vrf := gwAg.Status.State.BGP.VRFs[vrfName]
+		for _, neighIP := range slices.Sorted(maps.Keys(vrf.Neighbors)) {
+			neigh := vrf.Neighbors[neighIP]
+			if !neigh.Enabled {
+				continue
+			}
+
+			status.BGP.Neighbors++
+			if neigh.SessionState == gwintapi.BGPStateEstablished {
+				status.BGP.Established++
+			} else {
+				notEstablished = append(notEstablished, fmt.Sprintf("%s/%s", vrfName, neighIP))
+			}
+		}
+	}
+
+	alive := kmetav1.Condition{
+		Type:               gwapi.GatewayConditionAgentAlive,
+		Status:             kmetav1.ConditionTrue,
+		Reason:             gwapi.GatewayReasonAgentAlive,
+		Message:            "Agent heartbeat is fresh",
+		ObservedGeneration: gw.Generation,
+	}
+	switch {
+	case gwAg.Status.LastHeartbeat.IsZero():
+		alive.Status = kmetav1.ConditionFalse
+		alive.Reason = gwapi.GatewayReasonNoHeartbeat
+		alive.Message = "Agent has not reported any heartbeat yet"
+	case now.Sub(gwAg.Status.LastHeartbeat.Time) > AgentHeartbeatTimeout:
+		alive.Status = kmetav1.ConditionFalse
+		alive.Reason = gwapi.GatewayReasonStaleHeartbeat
+		alive.Message = fmt.Sprintf("Last agent heartbeat is older than %s", AgentHeartbeatTimeout)
+	}
+	kmeta.SetStatusCondition(&status.Conditions, alive)
+
+	applied := kmetav1.Condition{
+		Type:               gwapi.GatewayConditionConfigApplied,
+		Status:             kmetav1.ConditionTrue,
+		Reason:             gwapi.GatewayReasonApplied,
+		Message:            fmt.Sprintf("Agent applied generation %d", gwAg.Status.LastAppliedGen),
+		ObservedGeneration: gw.Generation,
+	}
+	if gwAg.Generation == 0 || gwAg.Status.LastAppliedGen < gwAg.Generation {
+		applied.Status = kmetav1.ConditionFalse
+		applied.Reason = gwapi.GatewayReasonApplyPending
+		applied.Message = fmt.Sprintf("Agent applied generation %d, desired %d", gwAg.Status.LastAppliedGen, gwAg.Generation)
+	}
+	kmeta.SetStatusCondition(&status.Conditions, applied)
+
+	bgp := kmetav1.Condition{
+		Type:               gwapi.GatewayConditionBGPEstablished,
+		Status:             kmetav1.ConditionTrue,
+		Reason:             gwapi.GatewayReasonEstablished,
+		Message:            fmt.Sprintf("All %d BGP sessions are established", status.BGP.Neighbors),
+		ObservedGeneration: gw.Generation,
+	}
+	switch {
+	case status.BGP.Neighbors == 0:
+		bgp.Status = kmetav1.ConditionUnknown
+		bgp.Reason = gwapi.GatewayReasonNoBGPState
+		bgp.Message = "Agent has not reported any BGP sessions yet"
+	case len(notEstablished) > 0:
+		bgp.Status = kmetav1.ConditionFalse
+		bgp.Reason = gwapi.GatewayReasonNotEstablished
+		bgp.Message = fmt.Sprintf("%d of %d BGP sessions are not established: %s",
+			len(notEstablished), status.BGP.Neighbors, strings.Join(notEstablished, ", "))
+	}
+	kmeta.SetStatusCondition(&status.Conditions, bgp)
+
+	ready := kmetav1.Condition{
+		Type:               gwapi.GatewayConditionReady,
+		Status:             kmetav1.ConditionTrue,
+		Reason:             gwapi.GatewayReasonReady,
+		Message:            "Gateway is ready",
+		ObservedGeneration: gw.Generation,
+	}
+	notReady := []string{}
+	for _, cond := range []kmetav1.Condition{alive, applied, bgp} {
+		if cond.Status != kmetav1.ConditionTrue {
+			notReady = append(notReady, cond.Type)
+		}
+	}
+	if len(notReady) > 0 {
+		ready.Status = kmetav1.ConditionFalse
+		ready.Reason = gwapi.GatewayReasonNotReady
+		ready.Message = "Not ready: " + strings.Join(notReady, ", ")
+	}
+	kmeta.SetStatusCondition(&status.Conditions, ready)
+
+	if gw.Spec.Drain.Enabled {
+		kmeta.SetStatusCondition(&status.Conditions, drainedCondition(gw, gwAg, applied))
+	} else {
+		kmeta.RemoveStatusCondition(&status.Conditions, gwapi.GatewayConditionDrained)
+	}
+
+	return !reflect.DeepEqual(orig, status)
+}
+
+// drainedCondition returns the Drained condition which is true once the agent applied the drain config and the total
+// peering traffic collected after that is below the drain threshold
+func drainedCondition(gw *gwapi.Gateway, gwAg *gwintapi.GatewayAgent, applied kmetav1.Condition) kmetav1.Condition {
+	drained := kmetav1.Condition{
+		Type:               gwapi.GatewayConditionDrained,
+		Status:             kmetav1.ConditionFalse,
+		Reason:             gwapi.GatewayReasonDraining,
+		ObservedGeneration: gw.Generation,
+	}
+
+	state := gwAg.Status.State
+	pps := 0.0
+	for _, peering := range state.Peerings {
+		pps += peering.PktsPerSecond
+	}
+
+	switch {
+	case applied.Status != kmetav1.ConditionTrue:
+		drained.Message = "Waiting for the agent to apply drain config"
+	case !state.LastCollectedTime.After(gwAg.Status.LastAppliedTime.Time):
+		drained.Message = "Waiting for the traffic stats collected after drain config is applied"
+	case pps >= float64(gw.Spec.Drain.ThresholdPPS):
+		drained.Message = fmt.Sprintf("Peering traffic is %.0f pps, waiting for it to be below %d pps", pps, gw.Spec.Drain.ThresholdPPS)
+	default:
+		drained.Status = kmetav1.ConditionTrue
+		drained.Reason = gwapi.GatewayReasonDrained
+		drained.Message = fmt.Sprintf("Peering traffic is %.0f pps, below %d pps", pps, gw.Spec.Drain.ThresholdPPS)
+	}
+
+	return drained
+}
@@ -0,0 +1,286 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestSetGatewayStatus(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	agent := func(f ...func(gwAg *gwintapi.GatewayAgent)) *gwintapi.GatewayAgent {
+		gwAg := &gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Generation: 3},
+			Status: gwintapi.GatewayAgentStatus{
+				AgentVersion:   "v1",
+				LastAppliedGen: 3,
+				LastHeartbeat:  kmetav1.NewTime(now.Add(-10 * time.Second)),
+				State: gwintapi.GatewayState{
+					Dataplane: gwintapi.DataplaneStatus{Version: "dp-v1"},
+					BGP: gwintapi.BGPStatus{
+						VRFs: map[string]gwintapi.BGPVRFStatus{
+							"default": {
+								Neighbors: map[string]gwintapi.BGPNeighborStatus{
+									"172.30.128.1": {Enabled: true, SessionState: gwintapi.BGPStateEstablished},
+									"172.30.128.5": {Enabled: true, SessionState: gwintapi.BGPStateEstablished},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		for _, fn := range f {
+			fn(gwAg)
+		}
+
+		return gwAg
+	}
+
+	for _, tt := range []struct {
+		name       string
+		gwAg       *gwintapi.GatewayAgent
+		conditions map[string]kmetav1.ConditionStatus
+	}{
+		{
+			name: "ready",
+			gwAg: agent(),
+			conditions: map[string]kmetav1.ConditionStatus{
+				gwapi.GatewayConditionReady:          kmetav1.ConditionTrue,
+				gwapi.GatewayConditionAgentAlive:     kmetav1.ConditionTrue,
+				gwapi.GatewayConditionConfigApplied:  kmetav1.ConditionTrue,
+				gwapi.GatewayConditionBGPEstablished: kmetav1.ConditionTrue,
+			},
+		},
+		{
+			name: "stale-heartbeat",
+			gwAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Status.LastHeartbeat = kmetav1.NewTime(now.Add(-AgentHeartbeatTimeout - time.Second))
+			}),
+			conditions: map[string]kmetav1.ConditionStatus{
+				gwapi.GatewayConditionReady:          kmetav1.ConditionFalse,
+				gwapi.GatewayConditionAgentAlive:     kmetav1.ConditionFalse,
+				gwapi.GatewayConditionConfigApplied:  kmetav1.ConditionTrue,
+				gwapi.GatewayConditionBGPEstablished: kmetav1.ConditionTrue,
+			},
+		},
+		{
+			name: "apply-pending",
+			gwAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Generation = 4
+			}),
+			conditions: map[string]kmetav1.ConditionStatus{
+				gwapi.GatewayConditionReady:          kmetav1.ConditionFalse,
+				gwapi.GatewayConditionAgentAlive:     kmetav1.ConditionTrue,
+				gwapi.GatewayConditionConfigApplied:  kmetav1.ConditionFalse,
+				gwapi.GatewayConditionBGPEstablished: kmetav1.ConditionTrue,
+			},
+		},
+		{
+			name: "bgp-down",
+			gwAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Status.State.BGP.VRFs["default"].Neighbors["172.30.128.5"] = gwintapi.BGPNeighborStatus{
+					Enabled:      true,
+					SessionState: gwintapi.BGPStateActive,
+				}
+			}),
+			conditions: map[string]kmetav1.ConditionStatus{
+				gwapi.GatewayConditionReady:          kmetav1.ConditionFalse,
+				gwapi.GatewayConditionAgentAlive:     kmetav1.ConditionTrue,
+				gwapi.GatewayConditionConfigApplied:  kmetav1.ConditionTrue,
+				gwapi.GatewayConditionBGPEstablished: kmetav1.ConditionFalse,
+			},
+		},
+		{
+			name: "new-agent",
+			gwAg: &gwintapi.GatewayAgent{ObjectMeta: kmetav1.ObjectMeta{Generation: 1}},
+			conditions: map[string]kmetav1.ConditionStatus{
+				gwapi.GatewayConditionReady:          kmetav1.ConditionFalse,
+				gwapi.GatewayConditionAgentAlive:     kmetav1.ConditionFalse,
+				gwapi.GatewayConditionConfigApplied:  kmetav1.ConditionFalse,
+				gwapi.GatewayConditionBGPEstablished: kmetav1.ConditionUnknown,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := &gwapi.Gateway{}
+
+			require.True(t, setGatewayStatus(gw, tt.gwAg, now), "status should be changed")
+			require.False(t, setGatewayStatus(gw, tt.gwAg, now), "status should not be changed on second call")
+
+			for condType, expected := range tt.conditions {
+				cond := kmeta.FindStatusCondition(gw.Status.Conditions, condType)
+				require.NotNil(t, cond, "condition %s should be set", condType)
+				require.Equal(t, expected, cond.Status, "condition %s status", condType)
+			}
+			require.Equal(t, tt.gwAg.Generation, gw.Status.DesiredGen)
+			require.Equal(t, tt.gwAg.Status.LastAppliedGen, gw.Status.LastAppliedGen)
+		})
+	}
+}
+
+func TestSetGatewayStatusDrained(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	applied := kmetav1.NewTime(now.Add(-time.Minute))
+
+	agent := func(gen int64, collected time.Time, pps float64) *gwintapi.GatewayAgent {
+		return &gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Generation: 5},
+			Status: gwintapi.GatewayAgentStatus{
+				LastAppliedGen:  gen,
+				LastAppliedTime: applied,
+				LastHeartbeat:   kmetav1.NewTime(now),
+				State: gwintapi.GatewayState{
+					LastCollectedTime: kmetav1.NewTime(collected),
+					Peerings: map[string]gwintapi.PeeringStatus{
+						"vpc-1->vpc-2": {PktsPerSecond: pps / 2},
+						"vpc-2->vpc-1": {PktsPerSecond: pps / 2},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		gwAg     *gwintapi.GatewayAgent
+		expected kmetav1.ConditionStatus
+	}{
+		{
+			name:     "not-applied",
+			gwAg:     agent(4, now, 0),
+			expected: kmetav1.ConditionFalse,
+		},
+		{
+			name:     "stale-stats",
+			gwAg:     agent(5, applied.Add(-time.Second), 0),
+			expected: kmetav1.ConditionFalse,
+		},
+		{
+			name:     "traffic-above-threshold",
+			gwAg:     agent(5, now, 1000),
+			expected: kmetav1.ConditionFalse,
+		},
+		{
+			name:     "drained",
+			gwAg:     agent(5, now, 10),
+			expected: kmetav1.ConditionTrue,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := &gwapi.Gateway{Spec: gwapi.GatewaySpec{Drain: gwapi.GatewayDrain{Enabled: true, ThresholdPPS: 100}}}
+
+			setGatewayStatus(gw, tt.gwAg, now)
+			cond := kmeta.FindStatusCondition(gw.Status.Conditions, gwapi.GatewayConditionDrained)
+			require.NotNil(t, cond)
+			require.Equal(t, tt.expected, cond.Status)
+
+			gw.Spec.Drain.Enabled = false
+			setGatewayStatus(gw, tt.gwAg, now)
+			require.Nil(t, kmeta.FindStatusCondition(gw.Status.Conditions, gwapi.GatewayConditionDrained), "condition should be removed")
+		})
+	}
+}
+
+func TestAgentStatusChanged(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	agent := func(f ...func(gwAg *gwintapi.GatewayAgent)) *gwintapi.GatewayAgent {
+		gwAg := &gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Generation: 3},
+			Status: gwintapi.GatewayAgentStatus{
+				AgentVersion:   "v1",
+				LastAppliedGen: 3,
+				LastHeartbeat:  kmetav1.NewTime(now),
+				State: gwintapi.GatewayState{
+					BGP: gwintapi.BGPStatus{
+						VRFs: map[string]gwintapi.BGPVRFStatus{
+							"default": {
+								Neighbors: map[string]gwintapi.BGPNeighborStatus{
+									"172.30.128.1": {Enabled: true, SessionState: gwintapi.BGPStateEstablished},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		for _, fn := range f {
+			fn(gwAg)
+		}
+
+		return gwAg
+	}
+
+	for _, tt := range []struct {
+		name     string
+		newAg    *gwintapi.GatewayAgent
+		expected bool
+	}{
+		{
+			name: "heartbeat",
+			newAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Status.LastHeartbeat = kmetav1.NewTime(now.Add(15 * time.Second))
+			}),
+		},
+		{
+			name: "counters",
+			newAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				neigh := gwAg.Status.State.BGP.VRFs["default"].Neighbors["172.30.128.1"]
+				neigh.Messages.Received.Keepalive = 10
+				gwAg.Status.State.BGP.VRFs["default"].Neighbors["172.30.128.1"] = neigh
+			}),
+		},
+		{
+			name: "heartbeat-after-stale",
+			newAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Status.LastHeartbeat = kmetav1.NewTime(now.Add(AgentHeartbeatTimeout + time.Second))
+			}),
+			expected: true,
+		},
+		{
+			name: "generation",
+			newAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Generation = 4
+			}),
+			expected: true,
+		},
+		{
+			name: "applied",
+			newAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Status.LastAppliedGen = 4
+			}),
+			expected: true,
+		},
+		{
+			name: "agent-version",
+			newAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Status.AgentVersion = "v2"
+			}),
+			expected: true,
+		},
+		{
+			name: "bgp-session",
+			newAg: agent(func(gwAg *gwintapi.GatewayAgent) {
+				gwAg.Status.State.BGP.VRFs["default"].Neighbors["172.30.128.1"] = gwintapi.BGPNeighborStatus{
+					Enabled: true, SessionState: gwintapi.BGPStateIdle,
+				}
+			}),
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, agentStatusChanged(event.UpdateEvent{ObjectOld: agent(), ObjectNew: tt.newAg}))
+		})
+	}
+}
@@ -6,13 +6,15 @@ package ctrl
 import (
 	"context"
 	"fmt"
+	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	kctrl "sigs.k8s.io/controller-runtime"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
-	"go.githedgehog.com/gateway/api/meta"
 )
 
 // +kubebuilder:webhook:path=/mutate-gateway-githedgehog-com-v1alpha1-gateway,mutating=true,failurePolicy=fail,sideEffects=None,groups=gateway.githedgehog.com,resources=gateways,verbs=create;update;delete,versions=v1alpha1,name=mgateway.kb.io,admissionReviewVersions=v1
@@ -20,13 +22,17 @@ import (
 
 type GatewayWebhook struct {
 	kclient.Reader
-	Cfg *meta.GatewayCtrlConfig
+	Cfgs *ConfigStore
 }
 
-func SetupGatewayWebhookWith(mgr kctrl.Manager, cfg *meta.GatewayCtrlConfig) error {
+func SetupGatewayWebhookWith(mgr kctrl.Manager, cfgs *ConfigStore) error {
+	if cfgs == nil {
+		return fmt.Errorf("gateway controller config store is nil") //nolint:goerr113
+	}
+
 	w := &GatewayWebhook{
 		Reader: mgr.GetClient(),
-		Cfg:    cfg,
+		Cfgs:   cfgs,
 	}
 
 	if err := kctrl.NewWebhookManagedBy(mgr, &gwapi.Gateway{}).
@@ -46,14 +52,119 @@ func (w *GatewayWebhook) Default(_ context.Context, obj *gwapi.Gateway) error {
 }
 
 func (w *GatewayWebhook) ValidateCreate(ctx context.Context, gw *gwapi.Gateway) (admission.Warnings, error) {
-	return nil, gw.Validate(ctx, w.Reader, w.Cfg) //nolint:wrapcheck
+	if err := gw.Validate(ctx, w.Reader, w.Cfgs.Get()); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return w.warnings(ctx, gw)
+}
+
+func (w *GatewayWebhook) ValidateUpdate(ctx context.Context, oldGw *gwapi.Gateway, newGw *gwapi.Gateway) (admission.Warnings, error) {
+	if newGw.DeletionTimestamp != nil {
+		// don't block finalizer removal but don't allow spec changes on a gateway that is going away either, old
+		// object is defaulted the same way as the new one to not fail on defaults introduced after it was stored
+		old := oldGw.DeepCopy()
+		old.Default()
+		if !reflect.DeepEqual(old.Spec, newGw.Spec) {
+			return nil, fmt.Errorf("gateway is being deleted, only metadata can be changed: %w", gwapi.ErrInvalidGW)
+		}
+
+		return nil, nil
+	}
+
+	if err := newGw.Validate(ctx, w.Reader, w.Cfgs.Get()); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	updateWarnings, err := newGw.ValidateUpdate(oldGw)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	warnings, err := w.warnings(ctx, newGw)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(updateWarnings, warnings...), nil
 }
 
-func (w *GatewayWebhook) ValidateUpdate(ctx context.Context, _ *gwapi.Gateway, newGw *gwapi.Gateway) (admission.Warnings, error) {
-	// TODO validate diff between oldObj and newObj if needed
-	return nil, newGw.Validate(ctx, w.Reader, w.Cfg) //nolint:wrapcheck
+func (w *GatewayWebhook) warnings(ctx context.Context, gw *gwapi.Gateway) (admission.Warnings, error) {
+	warnings, err := w.nodeWarnings(ctx, gw)
+	if err != nil {
+		return nil, err
+	}
+
+	drainWarnings, err := w.drainWarnings(ctx, gw)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(warnings, drainWarnings...), nil
 }
 
-func (w *GatewayWebhook) ValidateDelete(_ context.Context, _ *gwapi.Gateway) (admission.Warnings, error) {
+// drainWarnings returns warnings for the gateway groups that would be left without any non-drained members
+func (w *GatewayWebhook) drainWarnings(ctx context.Context, gw *gwapi.Gateway) (admission.Warnings, error) {
+	if !gw.Spec.Drain.Enabled {
+		return nil, nil
+	}
+
+	gws := &gwapi.GatewayList{}
+	if err := w.List(ctx, gws, kclient.InNamespace(gw.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing gateways: %w", err)
+	}
+
+	active := map[string]bool{}
+	for _, other := range gws.Items {
+		if other.Name == gw.Name || other.Spec.Drain.Enabled {
+			continue
+		}
+		for _, membership := range other.Spec.Groups {
+			active[membership.Name] = true
+		}
+	}
+
+	warnings := admission.Warnings{}
+	for _, membership := range gw.Spec.Groups {
+		if !active[membership.Name] {
+			warnings = append(warnings, fmt.Sprintf("gateway group %s has no other non-drained members, its traffic will not be shifted", membership.Name))
+		}
+	}
+
+	return warnings, nil
+}
+
+// nodeWarnings returns warnings if the gateway isn't going to be placed on any node, it's not an error as the node
+// could be added later
+func (w *GatewayWebhook) nodeWarnings(ctx context.Context, gw *gwapi.Gateway) (admission.Warnings, error) {
+	if gw.Spec.NodeName != "" {
+		node := &corev1.Node{}
+		if err := w.Get(ctx, kclient.ObjectKey{Name: gw.Spec.NodeName}, node); err != nil {
+			if kapierrors.IsNotFound(err) {
+				return admission.Warnings{fmt.Sprintf("node %s not found, gateway will not be running", gw.Spec.NodeName)}, nil
+			}
+
+			return nil, fmt.Errorf("getting node %s: %w", gw.Spec.NodeName, err)
+		}
+
+		return nil, nil
+	}
+
+	// it's the same selector used for the gateway pods
+	nodeSelector, _ := gatewayNodeAffinity(gw)
+	nodes := &corev1.NodeList{}
+	if err := w.List(ctx, nodes, kclient.MatchingLabels(nodeSelector)); err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	// matching more than one node is rejected by the validation
+	if len(nodes.Items) == 0 {
+		return admission.Warnings{fmt.Sprintf("no nodes match node selector %v, gateway will not be running", nodeSelector)}, nil
+	}
+
 	return nil, nil
 }
+
+func (w *GatewayWebhook) ValidateDelete(ctx context.Context, gw *gwapi.Gateway) (admission.Warnings, error) {
+	return gw.ValidateDelete(ctx, w.Reader) //nolint:wrapcheck
+}
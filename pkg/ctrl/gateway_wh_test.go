@@ -0,0 +1,70 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGatewayWebhookValidateUpdateDeleting(t *testing.T) {
+	gateway := func(f ...func(gw *gwapi.Gateway)) *gwapi.Gateway {
+		gw := &gwapi.Gateway{
+			ObjectMeta: kmetav1.ObjectMeta{
+				Namespace:  kmetav1.NamespaceDefault,
+				Name:       "gw-1",
+				Finalizers: []string{gatewayFinalizer},
+			},
+			Spec: gwapi.GatewaySpec{
+				ProtocolIP: "172.30.8.1/32",
+				VTEPIP:     "172.30.12.1/32",
+				ASN:        65534,
+			},
+		}
+		for _, fn := range f {
+			fn(gw)
+		}
+
+		return gw
+	}
+	deleting := func(gw *gwapi.Gateway) {
+		gw.DeletionTimestamp = &kmetav1.Time{}
+	}
+
+	for _, tt := range []struct {
+		name  string
+		newGw *gwapi.Gateway
+		err   bool
+	}{
+		{
+			name: "finalizer-removed",
+			newGw: gateway(deleting, func(gw *gwapi.Gateway) {
+				gw.Finalizers = nil
+				gw.Default()
+			}),
+		},
+		{
+			name: "spec-changed",
+			newGw: gateway(deleting, func(gw *gwapi.Gateway) {
+				gw.Spec.ASN = 65533
+				gw.Default()
+			}),
+			err: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &GatewayWebhook{}
+
+			_, err := w.ValidateUpdate(t.Context(), gateway(), tt.newGw)
+			if tt.err {
+				require.ErrorIs(t, err, gwapi.ErrInvalidGW)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	kctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gatewaygroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gatewaygroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gateways,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gwint.githedgehog.com,resources=gatewayagents,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch
+
+type GatewayGroupReconciler struct {
+	kclient.Client
+	cfgs *ConfigStore
+}
+
+func SetupGatewayGroupReconcilerWith(mgr kctrl.Manager, cfgs *ConfigStore) error {
+	r := &GatewayGroupReconciler{
+		Client: mgr.GetClient(),
+		cfgs:   cfgs,
+	}
+
+	if err := kctrl.NewControllerManagedBy(mgr).
+		Named("GatewayGroup").
+		For(&gwapi.GatewayGroup{}).
+		// membership, priorities and drain are coming from the gateways
+		Watches(&gwapi.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.enqueueGatewayGroups)).
+		// gateway agent has the same name as the gateway
+		Watches(&gwintapi.GatewayAgent{}, handler.EnqueueRequestsFromMapFunc(r.enqueueGatewayGroups)).
+		// only placement matters for the peerings count, traffic refreshes are skipped
+		Watches(&gwapi.Peering{}, handler.EnqueueRequestsFromMapFunc(enqueuePeeringGatewayGroup),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: gatewayConfigInputChanged})).
+		// communities are allocated from the controller config
+		WatchesRawSource(source.Channel(cfgs.subscribe(), handler.EnqueueRequestsFromMapFunc(r.enqueueAllGatewayGroups))).
+		Complete(r); err != nil {
+		return fmt.Errorf("setting up controller: %w", err)
+	}
+
+	return nil
+}
+
+// enqueueGatewayGroups enqueues all groups of the gateway or of the gateway with the same name as the agent, it's
+// called for both old and new objects on update so groups the gateway has left are reconciled too
+func (r *GatewayGroupReconciler) enqueueGatewayGroups(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	gw, ok := obj.(*gwapi.Gateway)
+	if !ok {
+		gw = &gwapi.Gateway{}
+		if err := r.Get(ctx, kclient.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}, gw); err != nil {
+			if !kapierrors.IsNotFound(err) {
+				kctrllog.FromContext(ctx).Error(err, "error getting gateway to reconcile its groups")
+
+				return nil
+			}
+
+			// gateway is gone so it's unknown which groups it was a member of
+			return r.enqueueAllGatewayGroups(ctx, obj)
+		}
+	}
+
+	res := []reconcile.Request{}
+	for _, membership := range gw.Spec.Groups {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: gw.Namespace,
+			Name:      membership.Name,
+		}})
+	}
+
+	return res
+}
+
+func (r *GatewayGroupReconciler) enqueueAllGatewayGroups(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	gwGroups := &gwapi.GatewayGroupList{}
+	if err := r.List(ctx, gwGroups, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing gateway groups to reconcile all")
+
+		return nil
+	}
+
+	res := []reconcile.Request{}
+	for _, gwGroup := range gwGroups.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: gwGroup.Namespace,
+			Name:      gwGroup.Name,
+		}})
+	}
+
+	return res
+}
+
+func enqueuePeeringGatewayGroup(_ context.Context, obj kclient.Object) []reconcile.Request {
+	peering, ok := obj.(*gwapi.Peering)
+	if !ok {
+		return nil
+	}
+
+	// it's called for both old and new objects so the group the peering is moved from is reconciled too
+	gwGroup := peering.AssignedGatewayGroup()
+	if gwGroup == "" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: ktypes.NamespacedName{
+		Namespace: peering.Namespace,
+		Name:      gwGroup,
+	}}}
+}
+
+func (r *GatewayGroupReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	gwGroup := &gwapi.GatewayGroup{}
+	if err := r.Get(ctx, req.NamespacedName, gwGroup); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return kctrl.Result{}, nil
+		}
+
+		return kctrl.Result{}, fmt.Errorf("getting gateway group: %w", err)
+	}
+
+	if gwGroup.DeletionTimestamp != nil {
+		return kctrl.Result{}, nil
+	}
+
+	gws := &gwapi.GatewayList{}
+	if err := r.List(ctx, gws, kclient.InNamespace(gwGroup.Namespace)); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing gateways: %w", err)
+	}
+
+	gwAgList := &gwintapi.GatewayAgentList{}
+	if err := r.List(ctx, gwAgList, kclient.InNamespace(gwGroup.Namespace)); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing gateway agents: %w", err)
+	}
+	gwAgs := map[string]*gwintapi.GatewayAgent{}
+	for idx := range gwAgList.Items {
+		gwAgs[gwAgList.Items[idx].Name] = &gwAgList.Items[idx]
+	}
+
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(gwGroup.Namespace)); err != nil {
+		return kctrl.Result{}, fmt.Errorf("listing peerings: %w", err)
+	}
+	assigned := 0
+	for _, peering := range peerings.Items {
+		if peering.AssignedGatewayGroup() == gwGroup.Name {
+			assigned++
+		}
+	}
+
+	if setGatewayGroupStatus(gwGroup, gws.Items, gwAgs, assigned, r.cfgs.Get().Communities, time.Now()) {
+		l.Info("Updating GatewayGroup status", "healthy", gwGroup.Status.Healthy, "active", gwGroup.Status.Active)
+
+		if err := r.Status().Update(ctx, gwGroup); err != nil {
+			return kctrl.Result{}, fmt.Errorf("updating gateway group status: %w", err)
+		}
+	}
+
+	// requeue to catch the agent heartbeats going stale
+	return kctrl.Result{RequeueAfter: AgentHeartbeatTimeout}, nil
+}
+
+// setGatewayGroupStatus computes the group members and their health from the gateways and their agents and allocates
+// communities to the members, it returns true if the status has been changed
+func setGatewayGroupStatus(gwGroup *gwapi.GatewayGroup, gws []gwapi.Gateway, gwAgs map[string]*gwintapi.GatewayAgent, peerings int, comms map[uint32]string, now time.Time) bool {
+	orig := gwGroup.Status.DeepCopy()
+	status := &gwGroup.Status
+
+	redundancy := gwGroup.Spec.Redundancy
+	redundancy.Default()
+
+	// nil instead of empty to match the status read back from the API server
+	status.Members = nil
+	for _, gw := range gws {
+		if gw.DeletionTimestamp != nil {
+			continue
+		}
+
+		for _, membership := range gw.Spec.Groups {
+			if membership.Name != gwGroup.Name {
+				continue
+			}
+
+			member := gwapi.GatewayGroupMemberStatus{
+				Name:     gw.Name,
+				Priority: membership.Priority,
+				Drained:  gw.Spec.Drain.Enabled,
+			}
+			if redundancy.Mode == gwapi.GatewayGroupModeECMP {
+				member.Weight = max(membership.Weight, 1)
+			}
+			if gwAg, exists := gwAgs[gw.Name]; exists {
+				member.Alive = !gwAg.Status.LastHeartbeat.IsZero() && now.Sub(gwAg.Status.LastHeartbeat.Time) <= AgentHeartbeatTimeout
+				member.Applied = gwAg.Generation > 0 && gwAg.Status.LastAppliedGen >= gwAg.Generation
+			}
+			status.Members = append(status.Members, member)
+		}
+	}
+	// the same order as used for the agent config
+	slices.SortFunc(status.Members, func(a, b gwapi.GatewayGroupMemberStatus) int {
+		if a.Priority == b.Priority {
+			return strings.Compare(a.Name, b.Name)
+		}
+
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+
+	status.Healthy = 0
+	status.Active = nil
+	unhealthy := []string{}
+	for _, member := range status.Members {
+		if !member.IsHealthy() {
+			unhealthy = append(unhealthy, member.Name)
+
+			continue
+		}
+
+		status.Healthy++
+		if member.Drained {
+			continue
+		}
+		if redundancy.Mode == gwapi.GatewayGroupModeECMP || len(status.Active) == 0 {
+			status.Active = append(status.Active, member.Name)
+		}
+	}
+	status.Peerings = peerings
+	status.Communities = allocateGatewayGroupCommunities(status.Communities, status.Members, comms)
+
+	minHealthy := int(gwGroup.Spec.MinHealthyMembers)
+	if minHealthy == 0 {
+		minHealthy = len(status.Members)
+	}
+
+	degraded := kmetav1.Condition{
+		Type:               gwapi.GatewayGroupConditionDegraded,
+		Status:             kmetav1.ConditionFalse,
+		Reason:             gwapi.GatewayGroupReasonHealthy,
+		Message:            fmt.Sprintf("%d of %d members are healthy", status.Healthy, len(status.Members)),
+		ObservedGeneration: gwGroup.Generation,
+	}
+	switch {
+	case len(status.Members) == 0:
+		degraded.Reason = gwapi.GatewayGroupReasonNoMembers
+		degraded.Message = "Group has no members"
+		// it's only a problem if there is any traffic to handle
+		if peerings > 0 {
+			degraded.Status = kmetav1.ConditionTrue
+			degraded.Message = fmt.Sprintf("Group has no members to handle %d peerings", peerings)
+		}
+	case status.Healthy < minHealthy:
+		degraded.Status = kmetav1.ConditionTrue
+		degraded.Reason = gwapi.GatewayGroupReasonUnhealthy
+		degraded.Message = fmt.Sprintf("%d of %d members are healthy, at least %d required, unhealthy: %s",
+			status.Healthy, len(status.Members), minHealthy, strings.Join(unhealthy, ", "))
+	}
+	kmeta.SetStatusCondition(&status.Conditions, degraded)
+
+	return !reflect.DeepEqual(orig, status)
+}
+
+// allocateGatewayGroupCommunities returns the member to community ID allocations, existing allocations are kept as
+// long as the community exists to avoid route churn, new members get their previous community if it's still free or
+// the lowest free one, allocations of the former members are only reclaimed if there are no free communities left
+func allocateGatewayGroupCommunities(prev map[string]uint32, members []gwapi.GatewayGroupMemberStatus, comms map[uint32]string) map[string]uint32 {
+	res := map[string]uint32{}
+	used := map[uint32]string{}
+	for name, id := range prev {
+		if _, exists := comms[id]; !exists {
+			continue
+		}
+		res[name] = id
+		used[id] = name
+	}
+
+	isMember := map[string]bool{}
+	for _, member := range members {
+		isMember[member.Name] = true
+	}
+
+	ids := slices.Sorted(maps.Keys(comms))
+	// members are sorted by priority and name so allocation is deterministic
+	for _, member := range members {
+		if _, exists := res[member.Name]; exists {
+			continue
+		}
+
+		free := slices.IndexFunc(ids, func(id uint32) bool { return used[id] == "" })
+		if free < 0 {
+			// reclaim the lowest community allocated to a former member
+			free = slices.IndexFunc(ids, func(id uint32) bool { return !isMember[used[id]] })
+		}
+		if free < 0 {
+			// group has more members than communities which is rejected by the webhook
+			continue
+		}
+
+		id := ids[free]
+		delete(res, used[id])
+		res[member.Name] = id
+		used[id] = member.Name
+	}
+
+	// nil instead of empty to match the status read back from the API server
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetGatewayGroupStatus(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	gw := func(name string, priority uint32, drained bool) gwapi.Gateway {
+		return gwapi.Gateway{
+			ObjectMeta: kmetav1.ObjectMeta{Name: name},
+			Spec: gwapi.GatewaySpec{
+				Groups: []gwapi.GatewayGroupMembership{{Name: "gr1", Priority: priority}},
+				Drain:  gwapi.GatewayDrain{Enabled: drained},
+			},
+		}
+	}
+	agent := func(heartbeat time.Duration, gen, applied int64) *gwintapi.GatewayAgent {
+		return &gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Generation: gen},
+			Status: gwintapi.GatewayAgentStatus{
+				LastHeartbeat:  kmetav1.NewTime(now.Add(-heartbeat)),
+				LastAppliedGen: applied,
+			},
+		}
+	}
+
+	gws := []gwapi.Gateway{
+		gw("gw-3", 2, false),
+		gw("gw-1", 1, false),
+		gw("gw-2", 0, true),
+		gw("gw-4", 3, false),
+		{ObjectMeta: kmetav1.ObjectMeta{Name: "other"}, Spec: gwapi.GatewaySpec{Groups: []gwapi.GatewayGroupMembership{{Name: "gr2"}}}},
+	}
+	gwAgs := map[string]*gwintapi.GatewayAgent{
+		"gw-1": agent(10*time.Second, 2, 2),
+		"gw-2": agent(10*time.Second, 2, 2),
+		"gw-3": agent(10*time.Second, 2, 2),
+		"gw-4": agent(AgentHeartbeatTimeout+time.Second, 2, 2),
+	}
+
+	for _, tt := range []struct {
+		name      string
+		spec      gwapi.GatewayGroupSpec
+		gws       []gwapi.Gateway
+		peerings  int
+		active    []string
+		healthy   int
+		degraded  kmetav1.ConditionStatus
+		reason    string
+		memberLen int
+	}{
+		{
+			name:      "active-standby-degraded",
+			gws:       gws,
+			peerings:  3,
+			active:    []string{"gw-1"},
+			healthy:   3,
+			degraded:  kmetav1.ConditionTrue,
+			reason:    gwapi.GatewayGroupReasonUnhealthy,
+			memberLen: 4,
+		},
+		{
+			name:      "ecmp-min-healthy",
+			spec:      gwapi.GatewayGroupSpec{Redundancy: gwapi.GatewayGroupRedundancy{Mode: gwapi.GatewayGroupModeECMP}, MinHealthyMembers: 3},
+			gws:       gws,
+			active:    []string{"gw-1", "gw-3"},
+			healthy:   3,
+			degraded:  kmetav1.ConditionFalse,
+			reason:    gwapi.GatewayGroupReasonHealthy,
+			memberLen: 4,
+		},
+		{
+			name:     "no-members-no-peerings",
+			degraded: kmetav1.ConditionFalse,
+			reason:   gwapi.GatewayGroupReasonNoMembers,
+		},
+		{
+			name:     "no-members-with-peerings",
+			peerings: 1,
+			degraded: kmetav1.ConditionTrue,
+			reason:   gwapi.GatewayGroupReasonNoMembers,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gwGroup := &gwapi.GatewayGroup{ObjectMeta: kmetav1.ObjectMeta{Name: "gr1"}, Spec: tt.spec}
+
+			require.True(t, setGatewayGroupStatus(gwGroup, tt.gws, gwAgs, tt.peerings, nil, now), "status should be changed")
+			require.False(t, setGatewayGroupStatus(gwGroup, tt.gws, gwAgs, tt.peerings, nil, now), "status should not be changed on second call")
+
+			require.Len(t, gwGroup.Status.Members, tt.memberLen)
+			if tt.memberLen > 0 {
+				require.Equal(t, "gw-2", gwGroup.Status.Members[0].Name, "members should be sorted by priority")
+			}
+			require.Equal(t, tt.active, gwGroup.Status.Active)
+			require.Equal(t, tt.healthy, gwGroup.Status.Healthy)
+			require.Equal(t, tt.peerings, gwGroup.Status.Peerings)
+
+			cond := kmeta.FindStatusCondition(gwGroup.Status.Conditions, gwapi.GatewayGroupConditionDegraded)
+			require.NotNil(t, cond)
+			require.Equal(t, tt.degraded, cond.Status)
+			require.Equal(t, tt.reason, cond.Reason)
+		})
+	}
+}
+
+func TestAllocateGatewayGroupCommunities(t *testing.T) {
+	comms := map[uint32]string{0: "50000:100", 1: "50000:101", 2: "50000:102"}
+	members := func(names ...string) []gwapi.GatewayGroupMemberStatus {
+		res := []gwapi.GatewayGroupMemberStatus{}
+		for _, name := range names {
+			res = append(res, gwapi.GatewayGroupMemberStatus{Name: name})
+		}
+
+		return res
+	}
+
+	for _, tt := range []struct {
+		name     string
+		prev     map[string]uint32
+		members  []gwapi.GatewayGroupMemberStatus
+		comms    map[uint32]string
+		expected map[string]uint32
+	}{
+		{
+			name:     "initial",
+			members:  members("gw-1", "gw-2"),
+			comms:    comms,
+			expected: map[string]uint32{"gw-1": 0, "gw-2": 1},
+		},
+		{
+			name:     "keep-existing",
+			prev:     map[string]uint32{"gw-2": 0},
+			members:  members("gw-1", "gw-2"),
+			comms:    comms,
+			expected: map[string]uint32{"gw-1": 1, "gw-2": 0},
+		},
+		{
+			name:     "keep-former-member-while-free",
+			prev:     map[string]uint32{"gw-1": 0, "gw-2": 1},
+			members:  members("gw-2", "gw-3"),
+			comms:    comms,
+			expected: map[string]uint32{"gw-1": 0, "gw-2": 1, "gw-3": 2},
+		},
+		{
+			name:     "readded-member-gets-same",
+			prev:     map[string]uint32{"gw-1": 0, "gw-2": 1},
+			members:  members("gw-1", "gw-2"),
+			comms:    comms,
+			expected: map[string]uint32{"gw-1": 0, "gw-2": 1},
+		},
+		{
+			name:     "reclaim-former-member",
+			prev:     map[string]uint32{"gw-1": 0, "gw-2": 1, "gw-3": 2},
+			members:  members("gw-2", "gw-3", "gw-4"),
+			comms:    comms,
+			expected: map[string]uint32{"gw-2": 1, "gw-3": 2, "gw-4": 0},
+		},
+		{
+			name:     "community-removed-from-config",
+			prev:     map[string]uint32{"gw-1": 5, "gw-2": 1},
+			members:  members("gw-1", "gw-2"),
+			comms:    comms,
+			expected: map[string]uint32{"gw-1": 0, "gw-2": 1},
+		},
+		{
+			name:     "too-many-members",
+			members:  members("gw-1", "gw-2", "gw-3", "gw-4"),
+			comms:    comms,
+			expected: map[string]uint32{"gw-1": 0, "gw-2": 1, "gw-3": 2},
+		},
+		{
+			name:    "no-communities",
+			members: members("gw-1"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, allocateGatewayGroupCommunities(tt.prev, tt.members, tt.comms))
+		})
+	}
+}
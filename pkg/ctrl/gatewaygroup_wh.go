@@ -52,6 +52,6 @@ func (w *GatewayGroupWebhook) ValidateUpdate(ctx context.Context, _ *gwapi.Gatew
 	return nil, newGwGr.Validate(ctx, w.Reader) //nolint:wrapcheck
 }
 
-func (w *GatewayGroupWebhook) ValidateDelete(_ context.Context, _ *gwapi.GatewayGroup) (admission.Warnings, error) {
-	return nil, nil
+func (w *GatewayGroupWebhook) ValidateDelete(ctx context.Context, gwGr *gwapi.GatewayGroup) (admission.Warnings, error) {
+	return gwGr.ValidateDelete(ctx, w.Reader) //nolint:wrapcheck
 }
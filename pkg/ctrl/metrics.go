@@ -0,0 +1,162 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "gateway_ctrl"
+
+	configReloadSuccess = "success"
+	configReloadError   = "error"
+
+	inventoryCollectTimeout = 5 * time.Second
+)
+
+var (
+	configReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "config_reloads_total",
+		Help:      "Number of config reload attempts by result",
+	}, []string{"result"})
+	configLastReloadSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "config_last_reload_successful",
+		Help:      "Whether the last config reload attempt succeeded",
+	})
+	gatewayReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "gateway_reconcile_duration_seconds",
+		Help:      "Duration of the gateway reconciles",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"gateway"})
+	gatewayAgentSpecSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "gateway_agent_spec_bytes",
+		Help:      "Size of the gateway agent spec in JSON",
+	}, []string{"gateway"})
+	peeringPropagationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "peering_propagation_seconds",
+		Help:      "Time from a peering change until it's applied by all gateway agents",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		configReloads,
+		configLastReloadSuccess,
+		gatewayReconcileDuration,
+		gatewayAgentSpecSize,
+		peeringPropagationDuration,
+	)
+
+	configLastReloadSuccess.Set(1)
+}
+
+// forgetGatewayMetrics removes all per-gateway series so deleted gateways don't keep being reported
+func forgetGatewayMetrics(gwName string) {
+	gatewayReconcileDuration.DeleteLabelValues(gwName)
+	gatewayAgentSpecSize.DeleteLabelValues(gwName)
+}
+
+var (
+	inventoryObjectsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "objects"),
+		"Number of objects by kind",
+		[]string{"kind"}, nil,
+	)
+	inventorySkippedPeeringsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "peerings_skipped"),
+		"Number of peerings not sent to the gateways by reason",
+		[]string{"reason"}, nil,
+	)
+	inventoryVPCIDsUsedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "vpc_ids_used"),
+		"Number of VPC internal IDs allocated",
+		nil, nil,
+	)
+	inventoryVPCIDsMaxDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "vpc_ids_max"),
+		"Number of VPC internal IDs available in total",
+		nil, nil,
+	)
+)
+
+// inventoryCollector reports the number of API objects on scrape using the manager cache
+type inventoryCollector struct {
+	kclient.Reader
+}
+
+var _ prometheus.Collector = (*inventoryCollector)(nil)
+
+func (c *inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- inventoryObjectsDesc
+	ch <- inventorySkippedPeeringsDesc
+	ch <- inventoryVPCIDsUsedDesc
+	ch <- inventoryVPCIDsMaxDesc
+}
+
+func (c *inventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryCollectTimeout)
+	defer cancel()
+
+	gws := &gwapi.GatewayList{}
+	gwGroups := &gwapi.GatewayGroupList{}
+	vpcs := &gwapi.VPCInfoList{}
+	peerings := &gwapi.PeeringList{}
+	for kind, list := range map[string]kclient.ObjectList{
+		"Gateway":      gws,
+		"GatewayGroup": gwGroups,
+		"VPCInfo":      vpcs,
+		"Peering":      peerings,
+	} {
+		// cache isn't started yet or something is really broken, nothing to report
+		if err := c.List(ctx, list); err != nil {
+			slog.Debug("Failed to list objects for metrics", "kind", kind, "error", err)
+
+			return
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(inventoryObjectsDesc, prometheus.GaugeValue, float64(len(gws.Items)), "Gateway")
+	ch <- prometheus.MustNewConstMetric(inventoryObjectsDesc, prometheus.GaugeValue, float64(len(gwGroups.Items)), "GatewayGroup")
+	ch <- prometheus.MustNewConstMetric(inventoryObjectsDesc, prometheus.GaugeValue, float64(len(vpcs.Items)), "VPCInfo")
+	ch <- prometheus.MustNewConstMetric(inventoryObjectsDesc, prometheus.GaugeValue, float64(len(peerings.Items)), "Peering")
+
+	vpcNames := map[string]bool{}
+	idsUsed := 0
+	for _, vpc := range vpcs.Items {
+		vpcNames[vpc.Name] = true
+		if vpc.Status.InternalID != "" {
+			idsUsed++
+		}
+	}
+
+	// it's the same check used by the gateway controller when building the agent spec
+	missingVPC := 0
+	for _, peering := range peerings.Items {
+		for vpcName := range peering.Spec.Peering {
+			if !vpcNames[vpcName] {
+				missingVPC++
+
+				break
+			}
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(inventorySkippedPeeringsDesc, prometheus.GaugeValue, float64(missingVPC), "missing_vpc")
+	ch <- prometheus.MustNewConstMetric(inventoryVPCIDsUsedDesc, prometheus.GaugeValue, float64(idsUsed))
+	ch <- prometheus.MustNewConstMetric(inventoryVPCIDsMaxDesc, prometheus.GaugeValue, float64(VPCID.GetMaxValue()))
+}
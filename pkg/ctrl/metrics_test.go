@@ -0,0 +1,63 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInventoryCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwapi.AddToScheme(scheme))
+
+	objMeta := func(name string) kmetav1.ObjectMeta {
+		return kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: name}
+	}
+	objs := []kclient.Object{
+		&gwapi.Gateway{ObjectMeta: objMeta("gw-1")},
+		&gwapi.Gateway{ObjectMeta: objMeta("gw-2")},
+		&gwapi.GatewayGroup{ObjectMeta: objMeta("default")},
+		&gwapi.VPCInfo{ObjectMeta: objMeta("vpc-1"), Status: gwapi.VPCInfoStatus{InternalID: "00001"}},
+		&gwapi.VPCInfo{ObjectMeta: objMeta("vpc-2"), Status: gwapi.VPCInfoStatus{InternalID: "00002"}},
+		&gwapi.VPCInfo{ObjectMeta: objMeta("vpc-3")},
+		&gwapi.Peering{ObjectMeta: objMeta("vpc-1--vpc-2"), Spec: gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {}, "vpc-2": {},
+		}}},
+		&gwapi.Peering{ObjectMeta: objMeta("vpc-1--vpc-4"), Spec: gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {}, "vpc-4": {},
+		}}},
+	}
+
+	c := &inventoryCollector{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+
+	expected := fmt.Sprintf(`
+# HELP gateway_ctrl_objects Number of objects by kind
+# TYPE gateway_ctrl_objects gauge
+gateway_ctrl_objects{kind="Gateway"} 2
+gateway_ctrl_objects{kind="GatewayGroup"} 1
+gateway_ctrl_objects{kind="Peering"} 2
+gateway_ctrl_objects{kind="VPCInfo"} 3
+# HELP gateway_ctrl_peerings_skipped Number of peerings not sent to the gateways by reason
+# TYPE gateway_ctrl_peerings_skipped gauge
+gateway_ctrl_peerings_skipped{reason="missing_vpc"} 1
+# HELP gateway_ctrl_vpc_ids_max Number of VPC internal IDs available in total
+# TYPE gateway_ctrl_vpc_ids_max gauge
+gateway_ctrl_vpc_ids_max %d
+# HELP gateway_ctrl_vpc_ids_used Number of VPC internal IDs allocated
+# TYPE gateway_ctrl_vpc_ids_used gauge
+gateway_ctrl_vpc_ids_used 2
+`, VPCID.GetMaxValue())
+
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
@@ -4,35 +4,65 @@
 package ctrl
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 
 	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	kctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=peerings/finalizers,verbs=update
 // +kubebuilder:rbac:groups=gateway.githedgehog.com,resources=gatewaygroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gwint.githedgehog.com,resources=gatewayagents,verbs=get;list;watch
+
+// peeringFinalizer is used to keep the peering until it's withdrawn from all gateways of its group
+const peeringFinalizer = "gateway.githedgehog.com/withdraw"
 
 type PeeringReconciler struct {
 	kclient.Client
+	recorder   events.EventRecorder
+	placements peeringPlacements
 }
 
 func SetupPeeringReconcilerWith(mgr kctrl.Manager) error {
 	r := &PeeringReconciler{
-		Client: mgr.GetClient(),
+		Client:   mgr.GetClient(),
+		recorder: mgr.GetEventRecorder(EventsReporter),
 	}
 
 	if err := kctrl.NewControllerManagedBy(mgr).
 		Named("Peering").
 		For(&gwapi.Peering{}).
+		// re-check peerings when the VPCs they reference are created or deleted
+		Watches(&gwapi.VPCInfo{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePeeringsForVPC)).
+		// place or move auto peerings when groups are created, deleted or change their members
+		Watches(&gwapi.GatewayGroup{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAutoPeerings)).
+		// track the peering propagation to the gateways, heartbeats and state updates are ignored
+		Watches(&gwintapi.GatewayAgent{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePeeringsForAgent),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: agentProgressChanged})).
 		Complete(r); err != nil {
 		return fmt.Errorf("setting up controller: %w", err)
 	}
@@ -40,6 +70,110 @@ func SetupPeeringReconcilerWith(mgr kctrl.Manager) error {
 	return nil
 }
 
+func (r *PeeringReconciler) enqueuePeeringsForVPC(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(obj.GetNamespace()), kclient.MatchingLabels{
+		gwapi.ListLabelVPC(obj.GetName()): gwapi.ListLabelValue,
+	}); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing peerings for vpc")
+
+		return nil
+	}
+
+	res := []reconcile.Request{}
+	for _, peering := range peerings.Items {
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: peering.Namespace,
+			Name:      peering.Name,
+		}})
+	}
+
+	return res
+}
+
+func (r *PeeringReconciler) enqueueAutoPeerings(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		kctrllog.FromContext(ctx).Error(err, "error listing peerings for gateway group")
+
+		return nil
+	}
+
+	res := []reconcile.Request{}
+	for _, peering := range peerings.Items {
+		if peering.Spec.GatewayGroup != gwapi.AutoGatewayGroup {
+			continue
+		}
+		// placed peerings only move if their group is gone or has no members
+		if peering.Status.GatewayGroup != "" && peering.Status.GatewayGroup != obj.GetName() {
+			continue
+		}
+
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: peering.Namespace,
+			Name:      peering.Name,
+		}})
+	}
+
+	return res
+}
+
+// enqueuePeeringsForAgent enqueues the peerings of the groups of the gateway with the same name as the agent and the
+// ones already propagated to it
+func (r *PeeringReconciler) enqueuePeeringsForAgent(ctx context.Context, obj kclient.Object) []reconcile.Request {
+	l := kctrllog.FromContext(ctx)
+
+	gwGroups := map[string]bool{}
+	gw := &gwapi.Gateway{}
+	if err := r.Get(ctx, kclient.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}, gw); err != nil {
+		if !kapierrors.IsNotFound(err) {
+			l.Error(err, "error getting gateway to reconcile its peerings")
+
+			return nil
+		}
+	}
+	for _, membership := range gw.Spec.Groups {
+		gwGroups[membership.Name] = true
+	}
+
+	peerings := &gwapi.PeeringList{}
+	if err := r.List(ctx, peerings, kclient.InNamespace(obj.GetNamespace())); err != nil {
+		l.Error(err, "error listing peerings for gateway agent")
+
+		return nil
+	}
+
+	res := []reconcile.Request{}
+	for _, peering := range peerings.Items {
+		if _, propagated := peering.Status.Propagation[obj.GetName()]; !propagated && !gwGroups[peering.AssignedGatewayGroup()] {
+			continue
+		}
+
+		res = append(res, reconcile.Request{NamespacedName: ktypes.NamespacedName{
+			Namespace: peering.Namespace,
+			Name:      peering.Name,
+		}})
+	}
+
+	return res
+}
+
+// agentProgressChanged returns true if the gateway agent config or the applied generations are changed
+func agentProgressChanged(evt event.UpdateEvent) bool {
+	oldAg, ok := evt.ObjectOld.(*gwintapi.GatewayAgent)
+	if !ok {
+		return true
+	}
+	newAg, ok := evt.ObjectNew.(*gwintapi.GatewayAgent)
+	if !ok {
+		return true
+	}
+
+	return oldAg.Generation != newAg.Generation ||
+		oldAg.Status.LastAppliedGen != newAg.Status.LastAppliedGen ||
+		oldAg.Status.State.FRR.LastAppliedGen != newAg.Status.State.FRR.LastAppliedGen
+}
+
 func (r *PeeringReconciler) Reconcile(ctx context.Context, req kctrl.Request) (kctrl.Result, error) {
 	l := kctrllog.FromContext(ctx)
 
@@ -53,9 +187,7 @@ func (r *PeeringReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 	}
 
 	if peering.DeletionTimestamp != nil {
-		l.Info("Peering is being deleted, skipping")
-
-		return kctrl.Result{}, nil
+		return r.withdrawPeering(ctx, peering)
 	}
 
 	{
@@ -73,8 +205,9 @@ func (r *PeeringReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 
 		orig := peering.DeepCopy()
 		peering.Default()
+		ctrlutil.AddFinalizer(peering, peeringFinalizer)
 		if !reflect.DeepEqual(orig, peering) {
-			l.Info("Applying defaults to Peering")
+			l.Info("Applying defaults and finalizer to Peering")
 
 			if err := r.Update(ctx, peering); err != nil {
 				return kctrl.Result{}, fmt.Errorf("updating peering: %w", err)
@@ -84,5 +217,286 @@ func (r *PeeringReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 
 	// l.Info("Reconciling Peering")
 
+	vpcs, missing, err := r.peeringVPCs(ctx, peering)
+	if err != nil {
+		return kctrl.Result{}, err
+	}
+	if len(missing) > 0 {
+		l.Info("Peered VPCs not found, peering will be skipped by gateways", "vpcs", missing)
+	}
+
+	gws, gwAgs, err := r.gatewaysWithAgents(ctx, peering.Namespace)
+	if err != nil {
+		return kctrl.Result{}, err
+	}
+
+	orig := peering.Status.DeepCopy()
+	if err := r.placePeering(ctx, peering); err != nil {
+		return kctrl.Result{}, err
+	}
+	members := peeringGateways(peering, gws)
+	peering.Status.Traffic, peering.Status.Gateways = peeringTraffic(peering, gws, gwAgs)
+	peering.Status.Exposed = peeringExposed(ctx, peering, vpcs)
+	pending := setPeeringPropagation(peering, members, gwAgs)
+	ready := peeringReadyCondition(peering, missing, members, pending)
+	// reported only when the set of missing VPCs changes and not on every refresh
+	if prev := kmeta.FindStatusCondition(orig.Conditions, gwapi.PeeringConditionReady); ready.Reason == gwapi.PeeringReasonVPCNotFound &&
+		(prev == nil || prev.Reason != ready.Reason || prev.Message != ready.Message) {
+		r.recorder.Eventf(peering, nil, corev1.EventTypeWarning, EventReasonVPCNotFound, "Reconcile",
+			"Peered VPCs not found, peering is skipped: %s", strings.Join(missing, ", "))
+	}
+	kmeta.SetStatusCondition(&peering.Status.Conditions, ready)
+	if !reflect.DeepEqual(orig, &peering.Status) {
+		if err := r.Status().Update(ctx, peering); err != nil {
+			return kctrl.Result{}, fmt.Errorf("updating peering status: %w", err)
+		}
+	}
+
+	if _, exists := peering.Annotations[gwapi.PeeringRebalanceAnnotation]; exists {
+		delete(peering.Annotations, gwapi.PeeringRebalanceAnnotation)
+		if err := r.Update(ctx, peering); err != nil {
+			return kctrl.Result{}, fmt.Errorf("removing rebalance annotation: %w", err)
+		}
+	}
+
+	// traffic isn't watched to avoid updating peerings on each agent heartbeat
+	return kctrl.Result{RequeueAfter: PeeringTrafficRefreshInterval}, nil
+}
+
+// withdrawPeering removes the finalizer once the peering being deleted is withdrawn from all gateways of its group,
+// it doesn't wait for the gateways if the deletion is forced using the annotation
+func (r *PeeringReconciler) withdrawPeering(ctx context.Context, peering *gwapi.Peering) (kctrl.Result, error) {
+	l := kctrllog.FromContext(ctx)
+
+	if !ctrlutil.ContainsFinalizer(peering, peeringFinalizer) {
+		return kctrl.Result{}, nil
+	}
+
+	gws, gwAgs, err := r.gatewaysWithAgents(ctx, peering.Namespace)
+	if err != nil {
+		return kctrl.Result{}, err
+	}
+
+	orig := peering.Status.DeepCopy()
+	pending := setPeeringPropagation(peering, peeringGateways(peering, gws), gwAgs)
+	removed := kmetav1.Condition{
+		Type:               gwapi.PeeringConditionRemoved,
+		Status:             kmetav1.ConditionTrue,
+		Reason:             gwapi.PeeringReasonWithdrawn,
+		Message:            "Withdrawn from all gateways",
+		ObservedGeneration: peering.Generation,
+	}
+	if len(pending) > 0 {
+		removed.Status = kmetav1.ConditionFalse
+		removed.Reason = gwapi.PeeringReasonPending
+		removed.Message = "Pending on " + strings.Join(pending, ", ")
+	}
+	kmeta.SetStatusCondition(&peering.Status.Conditions, removed)
+	if !reflect.DeepEqual(orig, &peering.Status) {
+		if err := r.Status().Update(ctx, peering); err != nil {
+			return kctrl.Result{}, fmt.Errorf("updating peering status: %w", err)
+		}
+	}
+
+	if len(pending) > 0 && peering.Annotations[gwapi.ForceDeleteAnnotation] != "true" {
+		l.Info("Peering is being deleted, waiting for it to be withdrawn", "pending", pending)
+
+		return kctrl.Result{}, nil
+	}
+
+	l.Info("Peering is withdrawn, removing finalizer")
+
+	ctrlutil.RemoveFinalizer(peering, peeringFinalizer)
+	if err := r.Update(ctx, peering); err != nil {
+		return kctrl.Result{}, fmt.Errorf("removing peering finalizer: %w", err)
+	}
+
 	return kctrl.Result{}, nil
 }
+
+// placePeering sets the group processing the peering in its status, the group for the auto gateway group is
+// picked based on the groups capacity and load and kept until the rebalance is requested
+func (r *PeeringReconciler) placePeering(ctx context.Context, peering *gwapi.Peering) error {
+	l := kctrllog.FromContext(ctx)
+
+	rebalance := peering.Annotations[gwapi.PeeringRebalanceAnnotation] == "true"
+
+	gwGroup := peering.Spec.GatewayGroup
+	if gwGroup == gwapi.AutoGatewayGroup {
+		gwGroupList := &gwapi.GatewayGroupList{}
+		if err := r.List(ctx, gwGroupList, kclient.InNamespace(peering.Namespace)); err != nil {
+			return fmt.Errorf("listing gateway groups: %w", err)
+		}
+		peerings := &gwapi.PeeringList{}
+		if err := r.List(ctx, peerings, kclient.InNamespace(peering.Namespace)); err != nil {
+			return fmt.Errorf("listing peerings: %w", err)
+		}
+		pending := r.placements.pending(peering.Namespace, peerings.Items)
+		gwGroup = pickGatewayGroup(peering, gwGroupList.Items, peerings.Items, pending, rebalance)
+		r.placements.place(kclient.ObjectKeyFromObject(peering), gwGroup)
+		if gwGroup == "" {
+			l.Info("No gateway group available for the peering")
+			// reported only on the first attempt or when the placement is lost and not on every refresh
+			if peering.Status.GatewayGroup != "" || kmeta.FindStatusCondition(peering.Status.Conditions, gwapi.PeeringConditionReady) == nil {
+				r.recorder.Eventf(peering, nil, corev1.EventTypeWarning, EventReasonNoGatewayGroup, "Place",
+					"No gateway group available for the peering, all are excluded, full or without members")
+			}
+		} else if gwGroup != peering.Status.GatewayGroup {
+			l.Info("Placing peering to gateway group", "group", gwGroup, "prev", peering.Status.GatewayGroup)
+			r.recorder.Eventf(peering, nil, corev1.EventTypeNormal, EventReasonPlaced, "Place",
+				"Placed to gateway group %s", gwGroup)
+		}
+	}
+
+	peering.Status.GatewayGroup = gwGroup
+
+	return nil
+}
+
+// gatewayGroupLoad is the load of a group used to pick the group for the auto placement
+type gatewayGroupLoad struct {
+	name     string
+	members  int
+	peerings int
+	bps      float64
+	pps      float64
+}
+
+// compare orders the groups by the traffic per member and then by the number of peerings per member, names are used
+// to make the order stable
+func (l gatewayGroupLoad) compare(o gatewayGroupLoad) int {
+	members, oMembers := float64(l.members), float64(o.members)
+
+	return cmp.Or(
+		cmp.Compare(l.bps/members, o.bps/oMembers),
+		cmp.Compare(l.pps/members, o.pps/oMembers),
+		cmp.Compare(float64(l.peerings)/members, float64(o.peerings)/oMembers),
+		strings.Compare(l.name, o.name),
+	)
+}
+
+// pickGatewayGroup returns the group for the peering with the auto gateway group, the current one is kept unless
+// rebalance is requested or the group is gone or has no members, otherwise the least loaded group that isn't excluded
+// and has room for more peerings is picked, it returns empty string if there is no such group, the load of a group is
+// counted from the peerings assigned to it with the pending placements keyed by the peering name taking precedence
+func pickGatewayGroup(peering *gwapi.Peering, gwGroups []gwapi.GatewayGroup, peerings []gwapi.Peering, pending map[string]string, rebalance bool) string {
+	loads := map[string]*gatewayGroupLoad{}
+	for _, gwGroup := range gwGroups {
+		if gwGroup.DeletionTimestamp != nil || len(gwGroup.Status.Members) == 0 {
+			continue
+		}
+
+		if !rebalance && gwGroup.Name == peering.Status.GatewayGroup {
+			return gwGroup.Name
+		}
+
+		loads[gwGroup.Name] = &gatewayGroupLoad{name: gwGroup.Name, members: len(gwGroup.Status.Members)}
+	}
+
+	for _, other := range peerings {
+		if other.Name == peering.Name {
+			continue
+		}
+		gwGroup, exists := pending[other.Name]
+		if !exists {
+			gwGroup = other.AssignedGatewayGroup()
+		}
+		load, exists := loads[gwGroup]
+		if !exists {
+			continue
+		}
+		load.peerings++
+		// traffic is measured on the group the peering was in, it's expected to follow the peering when it's moved
+		for _, traffic := range other.Status.Traffic {
+			load.bps += traffic.BytesPerSecond
+			load.pps += traffic.PktsPerSecond
+		}
+	}
+
+	var picked *gatewayGroupLoad
+	for _, gwGroup := range gwGroups {
+		load, exists := loads[gwGroup.Name]
+		if !exists || gwGroup.Spec.Placement.Exclude {
+			continue
+		}
+		if maxPeerings := gwGroup.Spec.Placement.MaxPeerings; maxPeerings > 0 && load.peerings >= int(maxPeerings) {
+			continue
+		}
+		if picked == nil || load.compare(*picked) < 0 {
+			picked = load
+		}
+	}
+	if picked == nil {
+		return ""
+	}
+
+	return picked.name
+}
+
+// peeringPlacements keeps the groups picked for the auto placed peerings until they show up in the cache, so the
+// peerings placed in a quick succession are spread across the groups instead of all landing on the same one
+type peeringPlacements struct {
+	m      sync.Mutex
+	groups map[ktypes.NamespacedName]string
+}
+
+// place records the group picked for the peering, empty group means the peering isn't placed anywhere
+func (p *peeringPlacements) place(key ktypes.NamespacedName, gwGroup string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if gwGroup == "" {
+		delete(p.groups, key)
+
+		return
+	}
+	if p.groups == nil {
+		p.groups = map[ktypes.NamespacedName]string{}
+	}
+	p.groups[key] = gwGroup
+}
+
+// pending returns the groups picked for the peerings in the namespace that the listed peerings don't reflect yet
+// keyed by the peering name, the placements already reflected or of the peerings that are gone are forgotten
+func (p *peeringPlacements) pending(namespace string, peerings []gwapi.Peering) map[string]string {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	pending := map[string]string{}
+	for key, gwGroup := range p.groups {
+		if key.Namespace != namespace {
+			continue
+		}
+		idx := slices.IndexFunc(peerings, func(peering gwapi.Peering) bool { return peering.Name == key.Name })
+		if idx < 0 || peerings[idx].Status.GatewayGroup == gwGroup {
+			delete(p.groups, key)
+
+			continue
+		}
+		pending[key.Name] = gwGroup
+	}
+
+	return pending
+}
+
+// peeringVPCs returns the VPCInfos of the peered VPCs and the sorted names of the ones without a VPCInfo, it's the
+// same check the gateway controller uses to skip peerings
+func (r *PeeringReconciler) peeringVPCs(ctx context.Context, peering *gwapi.Peering) (map[string]*gwapi.VPCInfo, []string, error) {
+	vpcs := map[string]*gwapi.VPCInfo{}
+	missing := []string{}
+	for _, vpcName := range slices.Sorted(maps.Keys(peering.Spec.Peering)) {
+		vpc := &gwapi.VPCInfo{}
+		if err := r.Get(ctx, kclient.ObjectKey{Namespace: peering.Namespace, Name: vpcName}, vpc); err != nil {
+			if kapierrors.IsNotFound(err) {
+				missing = append(missing, vpcName)
+
+				continue
+			}
+
+			return nil, nil, fmt.Errorf("getting vpcinfo %s: %w", vpcName, err)
+		}
+		vpcs[vpcName] = vpc
+	}
+
+	return vpcs, missing, nil
+}
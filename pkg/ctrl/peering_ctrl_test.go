@@ -0,0 +1,299 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	kctrl "sigs.k8s.io/controller-runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPeeringReconcileMissingVPC(t *testing.T) {
+	objMeta := func(name string) kmetav1.ObjectMeta {
+		return kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: name}
+	}
+	peering := func() *gwapi.Peering {
+		return &gwapi.Peering{ObjectMeta: objMeta("vpc-1--vpc-2"), Spec: gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{
+			"vpc-1": {}, "vpc-2": {},
+		}}}
+	}
+
+	for _, tt := range []struct {
+		name   string
+		objs   []kclient.Object
+		events []string
+	}{
+		{
+			name: "all-vpcs-exist",
+			objs: []kclient.Object{
+				peering(),
+				&gwapi.VPCInfo{ObjectMeta: objMeta("vpc-1")},
+				&gwapi.VPCInfo{ObjectMeta: objMeta("vpc-2")},
+			},
+		},
+		{
+			name: "missing-vpc",
+			objs: []kclient.Object{
+				peering(),
+				&gwapi.VPCInfo{ObjectMeta: objMeta("vpc-1")},
+			},
+			events: []string{"Warning VPCNotFound Peered VPCs not found, peering is skipped: vpc-2"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, gwapi.AddToScheme(scheme))
+			require.NoError(t, gwintapi.AddToScheme(scheme))
+
+			recorder := events.NewFakeRecorder(10)
+			r := &PeeringReconciler{
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objs...).WithStatusSubresource(&gwapi.Peering{}).Build(),
+				recorder: recorder,
+			}
+
+			// events are only recorded on changes and not on every refresh
+			for range 2 {
+				_, err := r.Reconcile(t.Context(), kctrl.Request{NamespacedName: kclient.ObjectKeyFromObject(peering())})
+				require.NoError(t, err)
+			}
+
+			close(recorder.Events)
+			var evts []string
+			for evt := range recorder.Events {
+				evts = append(evts, evt)
+			}
+			require.Equal(t, tt.events, evts)
+		})
+	}
+}
+
+func TestPickGatewayGroup(t *testing.T) {
+	group := func(name string, members int, placement gwapi.GatewayGroupPlacement) gwapi.GatewayGroup {
+		gwGroup := gwapi.GatewayGroup{ObjectMeta: kmetav1.ObjectMeta{Name: name}, Spec: gwapi.GatewayGroupSpec{Placement: placement}}
+		for idx := range members {
+			gwGroup.Status.Members = append(gwGroup.Status.Members, gwapi.GatewayGroupMemberStatus{Name: name + "-gw-" + string(rune('a'+idx))})
+		}
+
+		return gwGroup
+	}
+	placed := func(name, gwGroup string) gwapi.Peering {
+		return gwapi.Peering{
+			ObjectMeta: kmetav1.ObjectMeta{Name: name},
+			Spec:       gwapi.PeeringSpec{GatewayGroup: gwapi.AutoGatewayGroup},
+			Status:     gwapi.PeeringStatus{GatewayGroup: gwGroup},
+		}
+	}
+	withTraffic := func(peering gwapi.Peering, bps float64) gwapi.Peering {
+		peering.Status.Traffic = map[string]gwapi.PeeringTraffic{"vpc-1->vpc-2": {BytesPerSecond: bps}}
+
+		return peering
+	}
+
+	for _, tt := range []struct {
+		name      string
+		current   string
+		rebalance bool
+		gwGroups  []gwapi.GatewayGroup
+		peerings  []gwapi.Peering
+		pending   map[string]string
+		expected  string
+	}{
+		{
+			name:     "least-peerings-per-member",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 1, gwapi.GatewayGroupPlacement{}), group("gr2", 2, gwapi.GatewayGroupPlacement{})},
+			peerings: []gwapi.Peering{placed("p1", "gr1"), placed("p2", "gr2")},
+			expected: "gr2",
+		},
+		{
+			name:     "least-traffic-per-member",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 1, gwapi.GatewayGroupPlacement{}), group("gr2", 1, gwapi.GatewayGroupPlacement{})},
+			peerings: []gwapi.Peering{withTraffic(placed("p1", "gr1"), 10), placed("p2", "gr1"), withTraffic(placed("p3", "gr2"), 1000)},
+			expected: "gr1",
+		},
+		{
+			name:     "pending-placements",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 1, gwapi.GatewayGroupPlacement{}), group("gr2", 1, gwapi.GatewayGroupPlacement{})},
+			peerings: []gwapi.Peering{placed("p1", ""), placed("p2", "gr2")},
+			pending:  map[string]string{"p1": "gr1", "p2": "gr1"},
+			expected: "gr2",
+		},
+		{
+			name:     "pending-placements-respect-max-peerings",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 1, gwapi.GatewayGroupPlacement{MaxPeerings: 1})},
+			peerings: []gwapi.Peering{placed("p1", "")},
+			pending:  map[string]string{"p1": "gr1"},
+		},
+		{
+			name:     "skip-excluded-full-and-empty",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 1, gwapi.GatewayGroupPlacement{Exclude: true}), group("gr2", 1, gwapi.GatewayGroupPlacement{MaxPeerings: 1}), group("gr3", 0, gwapi.GatewayGroupPlacement{}), group("gr4", 1, gwapi.GatewayGroupPlacement{})},
+			peerings: []gwapi.Peering{placed("p1", "gr2"), placed("p2", "gr4"), placed("p3", "gr4")},
+			expected: "gr4",
+		},
+		{
+			name:     "no-group-available",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 0, gwapi.GatewayGroupPlacement{})},
+		},
+		{
+			name:     "sticky",
+			current:  "gr1",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 1, gwapi.GatewayGroupPlacement{MaxPeerings: 1}), group("gr2", 1, gwapi.GatewayGroupPlacement{})},
+			peerings: []gwapi.Peering{placed("p1", "gr1"), placed("p2", "gr1")},
+			expected: "gr1",
+		},
+		{
+			name:      "rebalance",
+			current:   "gr1",
+			rebalance: true,
+			gwGroups:  []gwapi.GatewayGroup{group("gr1", 1, gwapi.GatewayGroupPlacement{}), group("gr2", 1, gwapi.GatewayGroupPlacement{})},
+			peerings:  []gwapi.Peering{placed("p", "gr1"), placed("p1", "gr1")},
+			expected:  "gr2",
+		},
+		{
+			name:     "current-group-without-members",
+			current:  "gr1",
+			gwGroups: []gwapi.GatewayGroup{group("gr1", 0, gwapi.GatewayGroupPlacement{}), group("gr2", 1, gwapi.GatewayGroupPlacement{})},
+			expected: "gr2",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peering := placed("p", tt.current)
+			require.Equal(t, tt.expected, pickGatewayGroup(&peering, tt.gwGroups, tt.peerings, tt.pending, tt.rebalance))
+		})
+	}
+}
+
+func TestPeeringPlacementsPending(t *testing.T) {
+	peering := func(name, gwGroup string) gwapi.Peering {
+		return gwapi.Peering{ObjectMeta: kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: name}, Status: gwapi.PeeringStatus{GatewayGroup: gwGroup}}
+	}
+	key := func(name string) ktypes.NamespacedName {
+		return ktypes.NamespacedName{Namespace: kmetav1.NamespaceDefault, Name: name}
+	}
+
+	p := &peeringPlacements{}
+	p.place(key("p1"), "gr1")
+	p.place(key("p2"), "gr2")
+	p.place(key("p3"), "gr1")
+	p.place(key("p4"), "gr1")
+	p.place(key("p4"), "")
+	p.place(ktypes.NamespacedName{Namespace: "other", Name: "p1"}, "gr1")
+
+	peerings := []gwapi.Peering{peering("p1", ""), peering("p2", "gr2"), peering("p4", "")}
+	require.Equal(t, map[string]string{"p1": "gr1"}, p.pending(kmetav1.NamespaceDefault, peerings))
+	require.Len(t, p.groups, 2, "reflected and gone placements should be forgotten")
+
+	peerings[0].Status.GatewayGroup = "gr1"
+	require.Empty(t, p.pending(kmetav1.NamespaceDefault, peerings))
+	require.Len(t, p.groups, 1, "placements in other namespaces should be kept")
+}
+
+func TestPeeringReconcileRebalance(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwapi.AddToScheme(scheme))
+	require.NoError(t, gwintapi.AddToScheme(scheme))
+
+	objMeta := func(name string) kmetav1.ObjectMeta {
+		return kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: name}
+	}
+	gwGroup := func(name string) *gwapi.GatewayGroup {
+		return &gwapi.GatewayGroup{ObjectMeta: objMeta(name), Status: gwapi.GatewayGroupStatus{
+			Members: []gwapi.GatewayGroupMemberStatus{{Name: name + "-gw"}},
+		}}
+	}
+	peering := func(name string) *gwapi.Peering {
+		return &gwapi.Peering{ObjectMeta: objMeta(name), Spec: gwapi.PeeringSpec{
+			GatewayGroup: gwapi.AutoGatewayGroup,
+			Peering:      map[string]*gwapi.PeeringEntry{"vpc-1": {}, "vpc-2": {}},
+		}, Status: gwapi.PeeringStatus{GatewayGroup: "gr1"}}
+	}
+	rebalanced := peering("p1")
+	rebalanced.Annotations = map[string]string{gwapi.PeeringRebalanceAnnotation: "true"}
+
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&gwapi.Peering{}).WithObjects(
+		gwGroup("gr1"), gwGroup("gr2"), peering("p0"), rebalanced,
+		&gwapi.VPCInfo{ObjectMeta: objMeta("vpc-1")}, &gwapi.VPCInfo{ObjectMeta: objMeta("vpc-2")},
+	).Build()
+	recorder := events.NewFakeRecorder(10)
+	r := &PeeringReconciler{Client: kube, recorder: recorder}
+
+	_, err := r.Reconcile(t.Context(), kctrl.Request{NamespacedName: kclient.ObjectKeyFromObject(rebalanced)})
+	require.NoError(t, err)
+
+	actual := &gwapi.Peering{}
+	require.NoError(t, kube.Get(t.Context(), kclient.ObjectKeyFromObject(rebalanced), actual))
+	require.Equal(t, "gr2", actual.Status.GatewayGroup, "peering should be moved to the less loaded group")
+	require.NotContains(t, actual.Annotations, gwapi.PeeringRebalanceAnnotation, "rebalance annotation should be removed")
+
+	require.Len(t, recorder.Events, 1)
+	require.Equal(t, "Normal Placed Placed to gateway group gr2", <-recorder.Events)
+}
+
+func TestPeeringReconcileDelete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gwapi.AddToScheme(scheme))
+	require.NoError(t, gwintapi.AddToScheme(scheme))
+
+	objMeta := func(name string) kmetav1.ObjectMeta {
+		return kmetav1.ObjectMeta{Namespace: kmetav1.NamespaceDefault, Name: name}
+	}
+	spec := gwapi.PeeringSpec{GatewayGroup: "gr1", Peering: map[string]*gwapi.PeeringEntry{"vpc-1": {}, "vpc-2": {}}}
+
+	for _, tt := range []struct {
+		name     string
+		included bool
+		force    bool
+		removed  bool
+	}{
+		{name: "pending", included: true},
+		{name: "withdrawn", removed: true},
+		{name: "forced", included: true, force: true, removed: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peering := &gwapi.Peering{ObjectMeta: objMeta("p"), Spec: spec, Status: gwapi.PeeringStatus{GatewayGroup: "gr1"}}
+			peering.Finalizers = []string{peeringFinalizer}
+			peering.DeletionTimestamp = &kmetav1.Time{Time: time.Now()}
+			if tt.force {
+				peering.Annotations = map[string]string{gwapi.ForceDeleteAnnotation: "true"}
+			}
+			gwAg := &gwintapi.GatewayAgent{ObjectMeta: objMeta("gw-1")}
+			if tt.included {
+				gwAg.Spec.Peerings = map[string]gwapi.PeeringSpec{"p": spec}
+			}
+
+			kube := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&gwapi.Peering{}).WithObjects(
+				peering, gwAg,
+				&gwapi.Gateway{ObjectMeta: objMeta("gw-1"), Spec: gwapi.GatewaySpec{Groups: []gwapi.GatewayGroupMembership{{Name: "gr1"}}}},
+			).Build()
+			r := &PeeringReconciler{Client: kube, recorder: events.NewFakeRecorder(10)}
+
+			_, err := r.Reconcile(t.Context(), kctrl.Request{NamespacedName: kclient.ObjectKeyFromObject(peering)})
+			require.NoError(t, err)
+
+			actual := &gwapi.Peering{}
+			err = kube.Get(t.Context(), kclient.ObjectKeyFromObject(peering), actual)
+			if tt.removed {
+				require.True(t, kapierrors.IsNotFound(err), "peering should be removed once the finalizer is gone")
+
+				return
+			}
+			require.NoError(t, err)
+			cond := kmeta.FindStatusCondition(actual.Status.Conditions, gwapi.PeeringConditionRemoved)
+			require.NotNil(t, cond)
+			require.Equal(t, kmetav1.ConditionFalse, cond.Status)
+			require.Equal(t, "Pending on gw-1", cond.Message)
+		})
+	}
+}
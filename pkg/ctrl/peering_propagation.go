@@ -0,0 +1,109 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"sync"
+	"time"
+)
+
+// peeringPropagation tracks peering changes until all gateway agents report the agent generation including them
+// as applied to measure the end to end propagation time
+type peeringPropagation struct {
+	m        sync.Mutex
+	peerings map[string]*peeringRollout
+	observe  func(time.Duration)
+}
+
+type peeringRollout struct {
+	gen   int64
+	since time.Time
+	// pending is the agent generation including the peering change for each gateway, 0 if not sent yet
+	pending map[string]int64
+	// untracked is set for the rollouts that started before they were seen (e.g. before the controller restart), so
+	// they are remembered but never measured
+	untracked bool
+}
+
+// peeringTarget is the peering generation and the gateways (members of its group) expected to apply it
+type peeringTarget struct {
+	gen int64
+	gws []string
+}
+
+func newPeeringPropagation(observe func(time.Duration)) *peeringPropagation {
+	return &peeringPropagation{
+		peerings: map[string]*peeringRollout{},
+		observe:  observe,
+	}
+}
+
+// sent records that the peering generations are included into the gateway agent generation, new peering generations
+// are expected to be applied by all of the target gateways, any unknown peerings are forgotten. Peerings first seen
+// while the gateway already reports the agent generation applied have been rolled out before the tracking started
+// (e.g. on controller restart), so they aren't measured.
+func (p *peeringPropagation) sent(peerings map[string]peeringTarget, gwName string, agentGen, appliedGen int64, now time.Time) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for name := range p.peerings {
+		if _, exists := peerings[name]; !exists {
+			delete(p.peerings, name)
+		}
+	}
+
+	for name, target := range peerings {
+		rollout, exists := p.peerings[name]
+		if !exists || rollout.gen != target.gen {
+			rollout = &peeringRollout{
+				gen:       target.gen,
+				since:     now,
+				pending:   map[string]int64{},
+				untracked: !exists && appliedGen >= agentGen,
+			}
+			if !rollout.untracked {
+				for _, name := range target.gws {
+					rollout.pending[name] = 0
+				}
+			}
+			p.peerings[name] = rollout
+		}
+
+		if prev, pending := rollout.pending[gwName]; pending && prev == 0 {
+			rollout.pending[gwName] = agentGen
+		}
+	}
+}
+
+// applied records the agent generation applied by the gateway and reports the peerings applied by all gateways
+func (p *peeringPropagation) applied(gwName string, appliedGen int64, now time.Time) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, rollout := range p.peerings {
+		if agentGen, pending := rollout.pending[gwName]; pending && agentGen > 0 && appliedGen >= agentGen {
+			delete(rollout.pending, gwName)
+			p.done(rollout, now)
+		}
+	}
+}
+
+// forget drops the deleted gateway so rollouts aren't waiting for it forever
+func (p *peeringPropagation) forget(gwName string, now time.Time) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	for _, rollout := range p.peerings {
+		if _, pending := rollout.pending[gwName]; pending {
+			delete(rollout.pending, gwName)
+			p.done(rollout, now)
+		}
+	}
+}
+
+func (p *peeringPropagation) done(rollout *peeringRollout, now time.Time) {
+	if len(rollout.pending) == 0 && p.observe != nil {
+		p.observe(now.Sub(rollout.since))
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeeringPropagation(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time {
+		return start.Add(time.Duration(sec) * time.Second)
+	}
+
+	observed := []time.Duration{}
+	p := newPeeringPropagation(func(d time.Duration) {
+		observed = append(observed, d)
+	})
+	target := func(gen int64) map[string]peeringTarget {
+		return map[string]peeringTarget{"peer-1": {gen: gen, gws: []string{"gw-1", "gw-2"}}}
+	}
+
+	p.sent(target(1), "gw-1", 5, 4, at(0))
+	p.applied("gw-1", 5, at(1))
+	require.Empty(t, observed, "gw-2 hasn't got the peering yet")
+
+	p.sent(target(1), "gw-2", 3, 2, at(2))
+	p.applied("gw-2", 2, at(3))
+	require.Empty(t, observed, "gw-2 hasn't applied the peering yet")
+
+	p.applied("gw-2", 3, at(4))
+	require.Equal(t, []time.Duration{4 * time.Second}, observed)
+
+	// same generation shouldn't be measured again
+	p.sent(target(1), "gw-1", 5, 5, at(5))
+	p.applied("gw-1", 5, at(5))
+	require.Len(t, observed, 1)
+
+	// new generation is measured from the moment it's seen
+	p.sent(target(2), "gw-1", 6, 5, at(10))
+	p.applied("gw-1", 6, at(11))
+	p.forget("gw-2", at(13))
+	require.Equal(t, []time.Duration{4 * time.Second, 3 * time.Second}, observed, "deleted gateway shouldn't be waited for")
+
+	// deleted peerings are dropped
+	p.sent(map[string]peeringTarget{}, "gw-1", 7, 7, at(20))
+	require.Empty(t, p.peerings)
+}
+
+func TestPeeringPropagationRestart(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time {
+		return start.Add(time.Duration(sec) * time.Second)
+	}
+
+	observed := []time.Duration{}
+	p := newPeeringPropagation(func(d time.Duration) {
+		observed = append(observed, d)
+	})
+	target := func(gen int64) map[string]peeringTarget {
+		return map[string]peeringTarget{"peer-1": {gen: gen, gws: []string{"gw-1"}}}
+	}
+
+	// already applied before the controller started, nothing to measure
+	p.sent(target(1), "gw-1", 5, 5, at(0))
+	p.applied("gw-1", 5, at(1))
+	require.Empty(t, observed)
+
+	// but the next change is measured
+	p.sent(target(2), "gw-1", 6, 5, at(10))
+	p.applied("gw-1", 6, at(12))
+	require.Equal(t, []time.Duration{2 * time.Second}, observed)
+}
+
+func TestPeeringPropagationGroupMembers(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	observed := []time.Duration{}
+	p := newPeeringPropagation(func(d time.Duration) {
+		observed = append(observed, d)
+	})
+	peerings := map[string]peeringTarget{"peer-1": {gen: 1, gws: []string{"gw-1"}}}
+
+	// gw-2 isn't a member of the peering group, so it's not waited for
+	p.sent(peerings, "gw-2", 3, 2, start)
+	p.sent(peerings, "gw-1", 5, 4, start)
+	p.applied("gw-1", 5, start.Add(time.Second))
+	require.Equal(t, []time.Duration{time.Second}, observed)
+}
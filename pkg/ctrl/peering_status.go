@@ -0,0 +1,342 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PeeringTrafficRefreshInterval is how often the peering traffic is collected from the gateway agents
+const PeeringTrafficRefreshInterval = 30 * time.Second
+
+// gatewaysWithAgents returns all gateways and their agents keyed by the gateway name
+func (r *PeeringReconciler) gatewaysWithAgents(ctx context.Context, ns string) ([]gwapi.Gateway, map[string]*gwintapi.GatewayAgent, error) {
+	gws := &gwapi.GatewayList{}
+	if err := r.List(ctx, gws, kclient.InNamespace(ns)); err != nil {
+		return nil, nil, fmt.Errorf("listing gateways: %w", err)
+	}
+	gwAgList := &gwintapi.GatewayAgentList{}
+	if err := r.List(ctx, gwAgList, kclient.InNamespace(ns)); err != nil {
+		return nil, nil, fmt.Errorf("listing gateway agents: %w", err)
+	}
+	gwAgs := map[string]*gwintapi.GatewayAgent{}
+	for idx := range gwAgList.Items {
+		gwAgs[gwAgList.Items[idx].Name] = &gwAgList.Items[idx]
+	}
+
+	return gws.Items, gwAgs, nil
+}
+
+// peeringGateways returns the sorted names of the gateways in the group processing the peering
+func peeringGateways(peering *gwapi.Peering, gws []gwapi.Gateway) []string {
+	res := []string{}
+	if peering.Status.GatewayGroup == "" {
+		return res
+	}
+
+	for _, gw := range gws {
+		if gw.DeletionTimestamp != nil {
+			continue
+		}
+		if slices.ContainsFunc(gw.Spec.Groups, func(m gwapi.GatewayGroupMembership) bool { return m.Name == peering.Status.GatewayGroup }) {
+			res = append(res, gw.Name)
+		}
+	}
+	slices.Sort(res)
+
+	return res
+}
+
+// setPeeringPropagation records the first observed gateway agent generations including the current peering
+// generation (or not including the peering anymore if it's being deleted) and returns the gateways it isn't applied
+// on yet, both the agent and FRR have to apply the generation, missing agents have nothing to withdraw
+func setPeeringPropagation(peering *gwapi.Peering, gws []string, gwAgs map[string]*gwintapi.GatewayAgent) []string {
+	deleting := peering.DeletionTimestamp != nil
+
+	// nil instead of empty to match the status read back from the API server
+	var props map[string]gwapi.PeeringPropagation
+	pending := []string{}
+	for _, gwName := range gws {
+		gwAg, exists := gwAgs[gwName]
+		if !exists {
+			if !deleting {
+				pending = append(pending, gwName)
+			}
+
+			continue
+		}
+
+		spec, included := gwAg.Spec.Peerings[peering.Name]
+		prop, exists := peering.Status.Propagation[gwName]
+		if deleting {
+			if included {
+				pending = append(pending, gwName)
+
+				continue
+			}
+			if !exists || !prop.Withdrawn {
+				prop = gwapi.PeeringPropagation{AgentGeneration: gwAg.Generation, Withdrawn: true}
+			}
+		} else {
+			// agent config may be built from the previous peering generation
+			if !included || !reflect.DeepEqual(spec.Peering, peering.Spec.Peering) {
+				pending = append(pending, gwName)
+
+				continue
+			}
+			if !exists || prop.Withdrawn || prop.Generation != peering.Generation {
+				prop = gwapi.PeeringPropagation{Generation: peering.Generation, AgentGeneration: gwAg.Generation}
+			}
+		}
+
+		if props == nil {
+			props = map[string]gwapi.PeeringPropagation{}
+		}
+		props[gwName] = prop
+
+		if gwAg.Status.LastAppliedGen < prop.AgentGeneration || gwAg.Status.State.FRR.LastAppliedGen < prop.AgentGeneration {
+			pending = append(pending, gwName)
+		}
+	}
+	peering.Status.Propagation = props
+
+	return pending
+}
+
+// peeringReadyCondition returns the Ready condition of the peering based on its placement, VPCs and propagation
+func peeringReadyCondition(peering *gwapi.Peering, missingVPCs, gws, pending []string) kmetav1.Condition {
+	ready := kmetav1.Condition{
+		Type:               gwapi.PeeringConditionReady,
+		Status:             kmetav1.ConditionFalse,
+		ObservedGeneration: peering.Generation,
+	}
+
+	switch {
+	case len(missingVPCs) > 0:
+		ready.Reason = gwapi.PeeringReasonVPCNotFound
+		ready.Message = "Peered VPCs not found: " + strings.Join(missingVPCs, ", ")
+	case peering.Status.GatewayGroup == "":
+		ready.Reason = gwapi.PeeringReasonNotPlaced
+		ready.Message = "Peering isn't placed to a gateway group yet"
+	case len(gws) == 0:
+		ready.Reason = gwapi.PeeringReasonNoGateways
+		ready.Message = fmt.Sprintf("Gateway group %s has no gateways", peering.Status.GatewayGroup)
+	case len(pending) > 0:
+		ready.Reason = gwapi.PeeringReasonPending
+		ready.Message = "Pending on " + strings.Join(pending, ", ")
+	default:
+		ready.Status = kmetav1.ConditionTrue
+		ready.Reason = gwapi.PeeringReasonApplied
+		ready.Message = fmt.Sprintf("Applied on all %d gateways of group %s", len(gws), peering.Status.GatewayGroup)
+	}
+
+	return ready
+}
+
+// peeringTraffic returns the peering traffic per direction summed across the gateways of the peering group and per
+// gateway, agents report it per pair of VPCs keyed as VPC1->VPC2
+func peeringTraffic(peering *gwapi.Peering, gws []gwapi.Gateway, gwAgs map[string]*gwintapi.GatewayAgent) (map[string]gwapi.PeeringTraffic, map[string]map[string]gwapi.PeeringTraffic) {
+	vpcs := slices.Sorted(maps.Keys(peering.Spec.Peering))
+	if len(vpcs) != 2 {
+		return nil, nil
+	}
+	dirs := []string{vpcs[0] + "->" + vpcs[1], vpcs[1] + "->" + vpcs[0]}
+
+	// nil instead of empty to match the status read back from the API server
+	var total map[string]gwapi.PeeringTraffic
+	var perGw map[string]map[string]gwapi.PeeringTraffic
+	for _, gw := range gws {
+		if !slices.ContainsFunc(gw.Spec.Groups, func(m gwapi.GatewayGroupMembership) bool { return m.Name == peering.Status.GatewayGroup }) {
+			continue
+		}
+		gwAg, exists := gwAgs[gw.Name]
+		if !exists {
+			continue
+		}
+
+		for _, dir := range dirs {
+			state, exists := gwAg.Status.State.Peerings[dir]
+			if !exists {
+				continue
+			}
+
+			if total == nil {
+				total = map[string]gwapi.PeeringTraffic{}
+				perGw = map[string]map[string]gwapi.PeeringTraffic{}
+			}
+			if perGw[gw.Name] == nil {
+				perGw[gw.Name] = map[string]gwapi.PeeringTraffic{}
+			}
+			// rates are rounded so the status isn't rewritten because of a fractional jitter
+			traffic := gwapi.PeeringTraffic{
+				Packets:        state.Packets,
+				Bytes:          state.Bytes,
+				Drops:          state.Drops,
+				BytesPerSecond: math.Round(state.BytesPerSecond),
+				PktsPerSecond:  math.Round(state.PktsPerSecond),
+			}
+			perGw[gw.Name][dir] = traffic
+
+			sum := total[dir]
+			sum.Packets += traffic.Packets
+			sum.Bytes += traffic.Bytes
+			sum.Drops += traffic.Drops
+			sum.BytesPerSecond += traffic.BytesPerSecond
+			sum.PktsPerSecond += traffic.PktsPerSecond
+			total[dir] = sum
+		}
+	}
+
+	return total, perGw
+}
+
+// peeringExposed returns the effective expose config of the peering VPCs with the vpcSubnet entries replaced with the
+// subnet CIDRs and the not entries subtracted, subnets of the missing VPCs are skipped
+func peeringExposed(ctx context.Context, peering *gwapi.Peering, vpcs map[string]*gwapi.VPCInfo) map[string][]gwapi.PeeringExposeStatus {
+	l := kctrllog.FromContext(ctx)
+
+	var res map[string][]gwapi.PeeringExposeStatus
+	for vpcName, entry := range peering.Spec.Peering {
+		if entry == nil {
+			continue
+		}
+
+		for _, expose := range entry.Expose {
+			ips, ipNots := []netip.Prefix{}, []netip.Prefix{}
+			for _, ip := range expose.IPs {
+				switch {
+				case ip.CIDR != "":
+					ips = appendPrefix(ips, ip.CIDR)
+				case ip.Not != "":
+					ipNots = appendPrefix(ipNots, ip.Not)
+				case ip.VPCSubnet != "":
+					vpc := vpcs[vpcName]
+					if vpc == nil {
+						continue
+					}
+					subnet := vpc.Spec.Subnets[ip.VPCSubnet]
+					if subnet == nil {
+						l.Info("Exposed VPC subnet not found, skipping", "vpc", vpcName, "subnet", ip.VPCSubnet)
+
+						continue
+					}
+					ips = appendPrefix(ips, subnet.CIDR)
+				}
+			}
+
+			as, asNots := []netip.Prefix{}, []netip.Prefix{}
+			for _, entry := range expose.As {
+				switch {
+				case entry.CIDR != "":
+					as = appendPrefix(as, entry.CIDR)
+				case entry.Not != "":
+					asNots = appendPrefix(asNots, entry.Not)
+				}
+			}
+
+			status := gwapi.PeeringExposeStatus{
+				IPs:     prefixStrings(excludePrefixes(ips, ipNots)),
+				As:      prefixStrings(excludePrefixes(as, asNots)),
+				Default: expose.DefaultDestination,
+			}
+			if nat := expose.NAT; nat != nil {
+				switch {
+				case nat.Static != nil:
+					status.NAT = gwapi.PeeringExposeNATStatic
+				case nat.Masquerade != nil:
+					status.NAT = gwapi.PeeringExposeNATMasquerade
+				case nat.PortForward != nil:
+					status.NAT = gwapi.PeeringExposeNATPortForward
+				}
+			}
+
+			if res == nil {
+				res = map[string][]gwapi.PeeringExposeStatus{}
+			}
+			res[vpcName] = append(res[vpcName], status)
+		}
+	}
+
+	return res
+}
+
+// appendPrefix appends the parsed prefix, invalid ones are rejected by the webhook so they are just skipped
+func appendPrefix(prefixes []netip.Prefix, in string) []netip.Prefix {
+	prefix, err := netip.ParsePrefix(in)
+	if err != nil {
+		return prefixes
+	}
+
+	return append(prefixes, prefix.Masked())
+}
+
+// excludePrefixes returns the sorted prefixes covering the included ones without the excluded ones
+func excludePrefixes(include, exclude []netip.Prefix) []netip.Prefix {
+	res := []netip.Prefix{}
+	for _, prefix := range include {
+		res = append(res, excludeFromPrefix(prefix, exclude)...)
+	}
+
+	slices.SortFunc(res, func(a, b netip.Prefix) int {
+		return cmp.Or(a.Addr().Compare(b.Addr()), cmp.Compare(a.Bits(), b.Bits()))
+	})
+
+	return slices.Compact(res)
+}
+
+// excludeFromPrefix splits the prefix in halves until none of the parts partially overlaps with the excluded ones
+func excludeFromPrefix(prefix netip.Prefix, exclude []netip.Prefix) []netip.Prefix {
+	for _, not := range exclude {
+		if !not.Overlaps(prefix) {
+			continue
+		}
+		if not.Bits() <= prefix.Bits() {
+			return nil
+		}
+
+		low, high := splitPrefix(prefix)
+
+		return append(excludeFromPrefix(low, exclude), excludeFromPrefix(high, exclude)...)
+	}
+
+	return []netip.Prefix{prefix}
+}
+
+// splitPrefix returns the two halves of the prefix
+func splitPrefix(prefix netip.Prefix) (netip.Prefix, netip.Prefix) {
+	bits := prefix.Bits()
+	addr := prefix.Addr().AsSlice()
+	addr[bits/8] |= 0x80 >> (bits % 8)
+	high, _ := netip.AddrFromSlice(addr)
+
+	return netip.PrefixFrom(prefix.Addr(), bits+1), netip.PrefixFrom(high, bits+1)
+}
+
+func prefixStrings(prefixes []netip.Prefix) []string {
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	res := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		res = append(res, prefix.String())
+	}
+
+	return res
+}
@@ -0,0 +1,311 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	gwintapi "go.githedgehog.com/gateway/api/gwint/v1alpha1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExcludePrefixes(t *testing.T) {
+	prefixes := func(in ...string) []netip.Prefix {
+		res := []netip.Prefix{}
+		for _, prefix := range in {
+			res = append(res, netip.MustParsePrefix(prefix))
+		}
+
+		return res
+	}
+
+	for _, tt := range []struct {
+		name     string
+		include  []netip.Prefix
+		exclude  []netip.Prefix
+		expected []string
+	}{
+		{
+			name:     "no-excludes",
+			include:  prefixes("10.0.2.0/24", "10.0.1.0/24"),
+			expected: []string{"10.0.1.0/24", "10.0.2.0/24"},
+		},
+		{
+			name:     "exclude-part",
+			include:  prefixes("10.0.0.0/24"),
+			exclude:  prefixes("10.0.0.64/26"),
+			expected: []string{"10.0.0.0/26", "10.0.0.128/25"},
+		},
+		{
+			name:    "exclude-all",
+			include: prefixes("10.0.0.0/24"),
+			exclude: prefixes("10.0.0.0/16"),
+		},
+		{
+			name:     "exclude-single-ip",
+			include:  prefixes("10.0.0.0/30"),
+			exclude:  prefixes("10.0.0.3/32"),
+			expected: []string{"10.0.0.0/31", "10.0.0.2/32"},
+		},
+		{
+			name:     "ipv6",
+			include:  prefixes("fd00::/63"),
+			exclude:  prefixes("fd00:0:0:1::/64", "10.0.0.0/8"),
+			expected: []string{"fd00::/64"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, prefixStrings(excludePrefixes(tt.include, tt.exclude)))
+		})
+	}
+}
+
+func TestPeeringExposed(t *testing.T) {
+	peering := &gwapi.Peering{Spec: gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{
+		"vpc-1": {Expose: []gwapi.PeeringEntryExpose{{
+			IPs: []gwapi.PeeringEntryIP{{VPCSubnet: "subnet-1"}, {VPCSubnet: "subnet-missing"}, {Not: "10.0.1.0/25"}},
+			As:  []gwapi.PeeringEntryAs{{CIDR: "192.168.0.0/24"}, {Not: "192.168.0.128/25"}},
+			NAT: &gwapi.PeeringNAT{Static: &gwapi.PeeringNATStatic{}},
+		}}},
+		"vpc-2": {Expose: []gwapi.PeeringEntryExpose{
+			{IPs: []gwapi.PeeringEntryIP{{CIDR: "10.0.2.0/24"}}},
+			{DefaultDestination: true},
+		}},
+	}}}
+	vpcs := map[string]*gwapi.VPCInfo{
+		"vpc-1": {Spec: gwapi.VPCInfoSpec{Subnets: map[string]*gwapi.VPCInfoSubnet{"subnet-1": {CIDR: "10.0.1.0/24"}}}},
+	}
+
+	require.Equal(t, map[string][]gwapi.PeeringExposeStatus{
+		"vpc-1": {{IPs: []string{"10.0.1.128/25"}, As: []string{"192.168.0.0/25"}, NAT: gwapi.PeeringExposeNATStatic}},
+		"vpc-2": {{IPs: []string{"10.0.2.0/24"}}, {Default: true}},
+	}, peeringExposed(t.Context(), peering, vpcs))
+}
+
+func TestPeeringTraffic(t *testing.T) {
+	peering := &gwapi.Peering{
+		Spec:   gwapi.PeeringSpec{Peering: map[string]*gwapi.PeeringEntry{"vpc-1": {}, "vpc-2": {}}},
+		Status: gwapi.PeeringStatus{GatewayGroup: "gr1"},
+	}
+	gw := func(name, group string) gwapi.Gateway {
+		return gwapi.Gateway{ObjectMeta: kmetav1.ObjectMeta{Name: name}, Spec: gwapi.GatewaySpec{
+			Groups: []gwapi.GatewayGroupMembership{{Name: group}},
+		}}
+	}
+	agent := func(peerings map[string]gwintapi.PeeringStatus) *gwintapi.GatewayAgent {
+		return &gwintapi.GatewayAgent{Status: gwintapi.GatewayAgentStatus{State: gwintapi.GatewayState{Peerings: peerings}}}
+	}
+
+	gws := []gwapi.Gateway{gw("gw-1", "gr1"), gw("gw-2", "gr1"), gw("gw-3", "gr2"), gw("gw-4", "gr1")}
+	gwAgs := map[string]*gwintapi.GatewayAgent{
+		"gw-1": agent(map[string]gwintapi.PeeringStatus{
+			"vpc-1->vpc-2": {Packets: 10, Bytes: 1000, Drops: 1, BytesPerSecond: 100.4, PktsPerSecond: 0.6},
+			"vpc-2->vpc-1": {Packets: 5, Bytes: 500, PktsPerSecond: 0.2},
+			"vpc-1->vpc-3": {Packets: 100},
+		}),
+		"gw-2": agent(map[string]gwintapi.PeeringStatus{
+			"vpc-1->vpc-2": {Packets: 20, Bytes: 2000, BytesPerSecond: 199.6, PktsPerSecond: 2},
+		}),
+		"gw-3": agent(map[string]gwintapi.PeeringStatus{
+			"vpc-1->vpc-2": {Packets: 1000},
+		}),
+		"gw-4": agent(nil),
+	}
+
+	total, perGw := peeringTraffic(peering, gws, gwAgs)
+	require.Equal(t, map[string]gwapi.PeeringTraffic{
+		"vpc-1->vpc-2": {Packets: 30, Bytes: 3000, Drops: 1, BytesPerSecond: 300, PktsPerSecond: 3},
+		"vpc-2->vpc-1": {Packets: 5, Bytes: 500},
+	}, total)
+	require.Equal(t, map[string]map[string]gwapi.PeeringTraffic{
+		"gw-1": {
+			"vpc-1->vpc-2": {Packets: 10, Bytes: 1000, Drops: 1, BytesPerSecond: 100, PktsPerSecond: 1},
+			"vpc-2->vpc-1": {Packets: 5, Bytes: 500},
+		},
+		"gw-2": {
+			"vpc-1->vpc-2": {Packets: 20, Bytes: 2000, BytesPerSecond: 200, PktsPerSecond: 2},
+		},
+	}, perGw)
+}
+
+func TestSetPeeringPropagation(t *testing.T) {
+	spec := map[string]*gwapi.PeeringEntry{"vpc-1": {}, "vpc-2": {}}
+	agent := func(gen, applied, frrApplied int64, peerings map[string]gwapi.PeeringSpec) *gwintapi.GatewayAgent {
+		return &gwintapi.GatewayAgent{
+			ObjectMeta: kmetav1.ObjectMeta{Generation: gen},
+			Spec:       gwintapi.GatewayAgentSpec{Peerings: peerings},
+			Status: gwintapi.GatewayAgentStatus{
+				LastAppliedGen: applied,
+				State:          gwintapi.GatewayState{FRR: gwintapi.FRRStatus{LastAppliedGen: frrApplied}},
+			},
+		}
+	}
+	included := map[string]gwapi.PeeringSpec{"p": {GatewayGroup: "gr1", Peering: spec}}
+	outdated := map[string]gwapi.PeeringSpec{"p": {GatewayGroup: "gr1", Peering: map[string]*gwapi.PeeringEntry{"vpc-1": {}}}}
+
+	for _, tt := range []struct {
+		name     string
+		deleting bool
+		prev     map[string]gwapi.PeeringPropagation
+		gwAgs    map[string]*gwintapi.GatewayAgent
+		expected map[string]gwapi.PeeringPropagation
+		pending  []string
+	}{
+		{
+			name: "applied",
+			gwAgs: map[string]*gwintapi.GatewayAgent{
+				"gw-1": agent(5, 5, 5, included),
+				"gw-2": agent(3, 3, 3, included),
+			},
+			expected: map[string]gwapi.PeeringPropagation{
+				"gw-1": {Generation: 2, AgentGeneration: 5},
+				"gw-2": {Generation: 2, AgentGeneration: 3},
+			},
+			pending: []string{},
+		},
+		{
+			name: "pending",
+			gwAgs: map[string]*gwintapi.GatewayAgent{
+				"gw-1": agent(5, 4, 5, included),
+				"gw-2": agent(3, 3, 2, outdated),
+			},
+			expected: map[string]gwapi.PeeringPropagation{
+				"gw-1": {Generation: 2, AgentGeneration: 5},
+			},
+			pending: []string{"gw-1", "gw-2"},
+		},
+		{
+			name: "keep-first-observed",
+			prev: map[string]gwapi.PeeringPropagation{
+				"gw-1": {Generation: 2, AgentGeneration: 4},
+				"gw-2": {Generation: 1, AgentGeneration: 2},
+				"gw-3": {Generation: 2, AgentGeneration: 1},
+			},
+			gwAgs: map[string]*gwintapi.GatewayAgent{
+				"gw-1": agent(6, 4, 4, included),
+				"gw-2": agent(3, 2, 2, included),
+			},
+			expected: map[string]gwapi.PeeringPropagation{
+				"gw-1": {Generation: 2, AgentGeneration: 4},
+				"gw-2": {Generation: 2, AgentGeneration: 3},
+			},
+			pending: []string{"gw-2"},
+		},
+		{
+			name:     "withdrawn",
+			deleting: true,
+			prev: map[string]gwapi.PeeringPropagation{
+				"gw-1": {Generation: 2, AgentGeneration: 4},
+				"gw-2": {Generation: 2, AgentGeneration: 3},
+			},
+			gwAgs: map[string]*gwintapi.GatewayAgent{
+				"gw-1": agent(5, 5, 5, nil),
+			},
+			expected: map[string]gwapi.PeeringPropagation{
+				"gw-1": {AgentGeneration: 5, Withdrawn: true},
+			},
+			pending: []string{},
+		},
+		{
+			name:     "withdraw-pending",
+			deleting: true,
+			prev: map[string]gwapi.PeeringPropagation{
+				"gw-2": {AgentGeneration: 4, Withdrawn: true},
+			},
+			gwAgs: map[string]*gwintapi.GatewayAgent{
+				"gw-1": agent(5, 5, 5, included),
+				"gw-2": agent(6, 5, 5, nil),
+			},
+			expected: map[string]gwapi.PeeringPropagation{
+				"gw-2": {AgentGeneration: 4, Withdrawn: true},
+			},
+			pending: []string{"gw-1"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			peering := &gwapi.Peering{
+				ObjectMeta: kmetav1.ObjectMeta{Name: "p", Generation: 2},
+				Spec:       gwapi.PeeringSpec{GatewayGroup: "gr1", Peering: spec},
+				Status:     gwapi.PeeringStatus{GatewayGroup: "gr1", Propagation: tt.prev},
+			}
+			if tt.deleting {
+				peering.DeletionTimestamp = &kmetav1.Time{Time: time.Now()}
+			}
+
+			require.Equal(t, tt.pending, setPeeringPropagation(peering, []string{"gw-1", "gw-2"}, tt.gwAgs))
+			require.Equal(t, tt.expected, peering.Status.Propagation)
+		})
+	}
+}
+
+func TestPeeringReadyCondition(t *testing.T) {
+	peering := func(gwGroup string) *gwapi.Peering {
+		return &gwapi.Peering{Status: gwapi.PeeringStatus{GatewayGroup: gwGroup}}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		peering *gwapi.Peering
+		missing []string
+		gws     []string
+		pending []string
+		status  kmetav1.ConditionStatus
+		reason  string
+		message string
+	}{
+		{
+			name:    "ready",
+			peering: peering("gr1"),
+			gws:     []string{"gw-1", "gw-2"},
+			status:  kmetav1.ConditionTrue,
+			reason:  gwapi.PeeringReasonApplied,
+			message: "Applied on all 2 gateways of group gr1",
+		},
+		{
+			name:    "pending",
+			peering: peering("gr1"),
+			gws:     []string{"gw-1", "gw-2"},
+			pending: []string{"gw-2"},
+			status:  kmetav1.ConditionFalse,
+			reason:  gwapi.PeeringReasonPending,
+			message: "Pending on gw-2",
+		},
+		{
+			name:    "missing-vpc",
+			peering: peering("gr1"),
+			missing: []string{"vpc-2"},
+			gws:     []string{"gw-1"},
+			pending: []string{"gw-1"},
+			status:  kmetav1.ConditionFalse,
+			reason:  gwapi.PeeringReasonVPCNotFound,
+			message: "Peered VPCs not found: vpc-2",
+		},
+		{
+			name:    "not-placed",
+			peering: peering(""),
+			status:  kmetav1.ConditionFalse,
+			reason:  gwapi.PeeringReasonNotPlaced,
+			message: "Peering isn't placed to a gateway group yet",
+		},
+		{
+			name:    "no-gateways",
+			peering: peering("gr1"),
+			status:  kmetav1.ConditionFalse,
+			reason:  gwapi.PeeringReasonNoGateways,
+			message: "Gateway group gr1 has no gateways",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := peeringReadyCondition(tt.peering, tt.missing, tt.gws, tt.pending)
+			require.Equal(t, gwapi.PeeringConditionReady, cond.Type)
+			require.Equal(t, tt.status, cond.Status)
+			require.Equal(t, tt.reason, cond.Reason)
+			require.Equal(t, tt.message, cond.Message)
+		})
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"fmt"
 
 	gwapi "go.githedgehog.com/gateway/api/gateway/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
 	kctrl "sigs.k8s.io/controller-runtime"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	kctrllog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -19,11 +21,13 @@ import (
 
 type VPCInfoReconciler struct {
 	kclient.Client
+	recorder events.EventRecorder
 }
 
 func SetupVPCInfoReconcilerWith(mgr kctrl.Manager) error {
 	r := &VPCInfoReconciler{
-		Client: mgr.GetClient(),
+		Client:   mgr.GetClient(),
+		recorder: mgr.GetEventRecorder(EventsReporter),
 	}
 
 	if err := kctrl.NewControllerManagedBy(mgr).
@@ -89,6 +93,9 @@ func (r *VPCInfoReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 	}
 
 	if vpc.Status.InternalID == "" {
+		r.recorder.Eventf(vpc, nil, corev1.EventTypeWarning, EventReasonIDPoolExhausted, "AllocateID",
+			"No VPC internal ID available, all %d are in use", VPCID.GetMaxValue())
+
 		return kctrl.Result{}, fmt.Errorf("no available vpc id") //nolint:err113
 	}
 
@@ -96,5 +103,8 @@ func (r *VPCInfoReconciler) Reconcile(ctx context.Context, req kctrl.Request) (k
 		return kctrl.Result{}, fmt.Errorf("updating vpc status: %w", err)
 	}
 
+	r.recorder.Eventf(vpc, nil, corev1.EventTypeNormal, EventReasonIDAllocated, "AllocateID",
+		"Allocated VPC internal ID %s", vpc.Status.InternalID)
+
 	return kctrl.Result{}, nil
 }
@@ -0,0 +1,63 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// VPCInfoRetryMin is the initial delay to retry the gateway reconcile while waiting for a VPCInfo to be ready
+	VPCInfoRetryMin = 1 * time.Second
+	// VPCInfoRetryMax is the max delay to retry the gateway reconcile while waiting for a VPCInfo to be ready, the
+	// VPCInfo changes are watched anyway
+	VPCInfoRetryMax = 1 * time.Minute
+)
+
+// vpcInfoWaits tracks the gateways waiting for VPCInfos to be ready to only report when a gateway starts waiting for
+// another VPCInfo and to back off the retries, zero value is ready to use
+type vpcInfoWaits struct {
+	m     sync.Mutex
+	waits map[string]*vpcInfoWait
+}
+
+type vpcInfoWait struct {
+	vpc     string
+	retries int
+}
+
+// wait records the gateway waiting for the VPCInfo, it returns true if the gateway wasn't waiting for it before and
+// the delay to retry after
+func (w *vpcInfoWaits) wait(gwName, vpcName string) (bool, time.Duration) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.waits == nil {
+		w.waits = map[string]*vpcInfoWait{}
+	}
+
+	wait, exists := w.waits[gwName]
+	changed := !exists || wait.vpc != vpcName
+	if changed {
+		wait = &vpcInfoWait{vpc: vpcName}
+		w.waits[gwName] = wait
+	}
+
+	delay := VPCInfoRetryMax
+	if wait.retries < 16 {
+		delay = min(VPCInfoRetryMin<<wait.retries, VPCInfoRetryMax)
+	}
+	wait.retries++
+
+	return changed, delay
+}
+
+// done drops the gateway once it's not waiting for any VPCInfo or it's deleted
+func (w *vpcInfoWaits) done(gwName string) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	delete(w.waits, gwName)
+}
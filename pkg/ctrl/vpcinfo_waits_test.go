@@ -0,0 +1,40 @@
+// Copyright 2025 Hedgehog
+// SPDX-License-Identifier: Apache-2.0
+
+package ctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVPCInfoWaits(t *testing.T) {
+	w := &vpcInfoWaits{}
+
+	changed, delay := w.wait("gw-1", "vpc-1")
+	require.True(t, changed, "first wait should be reported")
+	require.Equal(t, VPCInfoRetryMin, delay)
+
+	changed, delay = w.wait("gw-1", "vpc-1")
+	require.False(t, changed, "waiting for the same VPCInfo shouldn't be reported again")
+	require.Equal(t, 2*VPCInfoRetryMin, delay)
+
+	for range 20 {
+		_, delay = w.wait("gw-1", "vpc-1")
+	}
+	require.Equal(t, VPCInfoRetryMax, delay)
+
+	changed, delay = w.wait("gw-1", "vpc-2")
+	require.True(t, changed, "waiting for another VPCInfo should be reported")
+	require.Equal(t, VPCInfoRetryMin, delay)
+
+	changed, _ = w.wait("gw-2", "vpc-2")
+	require.True(t, changed, "gateways are tracked separately")
+
+	w.done("gw-1")
+	changed, delay = w.wait("gw-1", "vpc-2")
+	require.True(t, changed, "waiting again after the VPCInfo was ready should be reported")
+	require.Equal(t, time.Second, delay)
+}
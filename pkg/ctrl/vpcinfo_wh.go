@@ -52,6 +52,6 @@ func (w *VPCInfoWebhook) ValidateUpdate(ctx context.Context, _ *gwapi.VPCInfo, n
 	return nil, newVPC.Validate(ctx, w.Reader) //nolint:wrapcheck
 }
 
-func (w *VPCInfoWebhook) ValidateDelete(_ context.Context, _ *gwapi.VPCInfo) (admission.Warnings, error) {
-	return nil, nil
+func (w *VPCInfoWebhook) ValidateDelete(ctx context.Context, vpc *gwapi.VPCInfo) (admission.Warnings, error) {
+	return vpc.ValidateDelete(ctx, w.Reader) //nolint:wrapcheck
 }